@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lambda0x63/sship/internal/api"
+	"github.com/lambda0x63/sship/internal/audit"
 	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/secrets"
+	"github.com/lambda0x63/sship/internal/ssh"
 	"github.com/lambda0x63/sship/internal/web"
 )
 
@@ -21,6 +30,15 @@ func main() {
 	var (
 		port        = flag.String("port", "9999", "웹 서버 포트")
 		configPath  = flag.String("config", "sship.yaml", "설정 파일 경로")
+		knownHosts  = flag.String("known-hosts", "", "호스트 키 저장 파일 경로 (기본: 설정 파일과 같은 디렉토리의 sship_known_hosts)")
+		auditPath   = flag.String("audit-log", "", "셸 세션 감사 로그 파일 경로 (기본: 설정 파일과 같은 디렉토리의 sship_audit.log)")
+		sshIdle     = flag.Duration("ssh-idle-timeout", 5*time.Minute, "사용되지 않는 SSH 연결을 닫기까지의 시간")
+		secretsPath = flag.String("secrets", "", "암호화된 시크릿 파일 경로 (기본: 설정 파일과 같은 디렉토리의 sship_secrets.enc)")
+		masterKey   = flag.String("master-key-file", "", "시크릿 마스터 키 파일 (SSHIP_MASTER_KEY 환경변수가 우선)")
+		configPoll  = flag.Duration("config-poll", 2*time.Second, "설정 파일 변경 확인 주기 (0이면 SIGHUP 에서만 다시 로드)")
+		importDir   = flag.String("import-legacy", "", "이전 configs/ 디렉토리의 projects/*.yaml 을 설정 파일로 가져온 뒤 종료")
+		dryRun      = flag.Bool("dry-run", false, "-import-legacy 에서 설정을 바꾸지 않고 바뀔 내용만 표시")
+		overwrite   = flag.Bool("overwrite", false, "-import-legacy 에서 이미 있는 프로젝트에 이전 설정을 덮어씀")
 		showVersion = flag.Bool("version", false, "버전 정보 표시")
 	)
 	flag.Parse()
@@ -30,18 +48,84 @@ func main() {
 		os.Exit(0)
 	}
 
-	cfg, err := config.LoadConfig(*configPath)
+	// 설정의 secret:NAME 참조를 해석하려면 설정보다 먼저 열어야 함
+	var secretStore *secrets.Store
+	key, err := secrets.LoadMasterKey(*masterKey)
 	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if key != "" {
+		if *secretsPath == "" {
+			*secretsPath = filepath.Join(filepath.Dir(*configPath), "sship_secrets.enc")
+		}
+		if secretStore, err = secrets.Open(*secretsPath, key); err != nil {
+			fmt.Printf("❌ 시크릿 저장소 초기화 실패: %v\n", err)
+			os.Exit(1)
+		}
+		config.UseSecrets(secretStore)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if errors.Is(err, os.ErrNotExist) {
 		// 설정 파일이 없으면 빈 설정으로 시작
 		cfg = &config.Config{
 			Projects: make(map[string]config.Project),
 		}
 		cfg.SetFilePath(*configPath)
+	} else if err != nil {
+		// 잘못된 설정으로 시작하면 이후 저장 시 기존 파일을 덮어쓰게 되므로 중단
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
 	}
 
+	if *importDir != "" {
+		report, err := cfg.ImportLegacy(*importDir, config.ImportOptions{DryRun: *dryRun, Overwrite: *overwrite})
+		fmt.Print(report)
+		if err != nil {
+			fmt.Printf("❌ 가져오기 실패: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *knownHosts == "" {
+		*knownHosts = filepath.Join(filepath.Dir(*configPath), "sship_known_hosts")
+	}
+	if err := ssh.UseKnownHostsFile(*knownHosts); err != nil {
+		fmt.Printf("❌ 호스트 키 저장소 초기화 실패: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *auditPath == "" {
+		*auditPath = filepath.Join(filepath.Dir(*configPath), "sship_audit.log")
+	}
+	auditLog, err := audit.NewLog(*auditPath)
+	if err != nil {
+		fmt.Printf("❌ 감사 로그 초기화 실패: %v\n", err)
+		os.Exit(1)
+	}
+
+	pool := ssh.NewPool(*sshIdle, 30*time.Second)
+	defer pool.Close()
+
 	router := gin.Default()
 
-	apiHandler := api.NewHandler(cfg)
+	apiHandler := api.NewHandler(cfg, pool, auditLog, secretStore)
+
+	// 설정 파일이 바뀌거나 SIGHUP 을 받으면 재시작 없이 다시 로드
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if *configPoll > 0 {
+		go cfg.Watch(watchCtx, *configPoll, apiHandler.ConfigReloaded)
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			apiHandler.ConfigReloaded(cfg.Reload())
+		}
+	}()
 
 	// Use embedded files
 	router.StaticFS("/static", getStaticFS())
@@ -64,17 +148,57 @@ func main() {
 		v1.POST("/project/:name/deploy", apiHandler.DeployProject)
 		v1.GET("/project/:name/logs", apiHandler.GetProjectLogs)
 		v1.POST("/project/:name/rollback", apiHandler.RollbackProject)
+		v1.GET("/project/:name/history", apiHandler.GetDeployHistory)
 		v1.GET("/ws/logs/:name", apiHandler.StreamLogs)
+		v1.GET("/ws/shell/:name", apiHandler.OpenShell)
+		v1.GET("/project/:name/services", apiHandler.ListComposeServices)
+		v1.GET("/ws/exec/:name/:service", apiHandler.ExecService)
+
+		// 프로젝트 파일 API (SFTP)
+		v1.GET("/project/:name/files", apiHandler.ListProjectFiles)
+		v1.GET("/project/:name/files/content", apiHandler.DownloadProjectFile)
+		v1.PUT("/project/:name/files/content", apiHandler.UploadProjectFile)
 		
 		// 프로젝트 관리 API
 		v1.PUT("/project/:name", apiHandler.AddProject)
 		v1.PATCH("/project/:name", apiHandler.UpdateProject)
 		v1.DELETE("/project/:name", apiHandler.DeleteProject)
 		v1.POST("/test-connection", apiHandler.TestConnection)
+		v1.POST("/config/validate", apiHandler.ValidateConfig)
+
+		// 설정 파일 이력 API
+		v1.GET("/config/revisions", apiHandler.ListConfigRevisions)
+		v1.GET("/config/revisions/:id", apiHandler.GetConfigRevision)
+		v1.POST("/config/revisions/:id/restore", apiHandler.RestoreConfigRevision)
+		v1.GET("/config/diff", apiHandler.DiffConfigRevisions)
+		v1.POST("/config/import-legacy", apiHandler.ImportLegacyConfig)
+
+		// 호스트 키 관리 API
+		v1.GET("/known-hosts", apiHandler.ListKnownHosts)
+		v1.POST("/known-hosts", apiHandler.PinHostKey)
+		v1.DELETE("/known-hosts", apiHandler.DeleteKnownHost)
 		
 		// 배포 상태 API
 		v1.GET("/deploy/active", apiHandler.GetActiveJobs)
 		v1.GET("/deploy/events", apiHandler.StreamDeployEvents)
+		v1.POST("/deploy/:id/cancel", apiHandler.CancelDeploy)
+
+		// SSH 연결 풀 상태
+		v1.GET("/ssh/pool", apiHandler.GetPoolStats)
+
+		// 셸 세션 감사 로그
+		v1.GET("/audit", apiHandler.ListAuditLog)
+
+		// 시크릿 관리 API (값은 조회할 수 없음)
+		v1.GET("/secrets", apiHandler.ListSecrets)
+		v1.PUT("/secrets/:name", apiHandler.PutSecret)
+		v1.DELETE("/secrets/:name", apiHandler.DeleteSecret)
+
+		// 공유 서버 관리 API
+		v1.GET("/servers", apiHandler.ListServers)
+		v1.GET("/servers/:name", apiHandler.GetServer)
+		v1.PUT("/servers/:name", apiHandler.PutServer)
+		v1.DELETE("/servers/:name", apiHandler.DeleteServer)
 	}
 
 	fmt.Printf("🌐 sship 웹 UI 시작: http://localhost:%s\n", *port)
@@ -97,6 +97,13 @@ function dashboardApp() {
                             // 서비스 목록 새로고침
                             setTimeout(() => this.loadServices(), 2000);
                             break;
+                        case 'config.changed':
+                            // sship.yaml 이 다시 로드됨
+                            this.loadServices();
+                            break;
+                        case 'config.invalid':
+                            console.error('설정 다시 로드 실패:', data.message);
+                            break;
                     }
                 } catch (error) {
                     console.error('이벤트 파싱 오류:', error);
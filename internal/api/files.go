@@ -0,0 +1,227 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/ssh"
+)
+
+// 업로드 최대 크기 (설정/compose/env 파일 용도)
+const maxUploadSize = 10 << 20
+
+// 다운로드 최대 크기 (로그 등 큰 파일은 셸로 확인)
+const maxDownloadSize = 50 << 20
+
+// 프로젝트 디렉토리의 파일 목록 또는 파일 정보 조회
+func (h *Handler) ListProjectFiles(c *gin.Context) {
+	projectName := targetName(c)
+
+	proj, exists := h.config.GetProject(projectName)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
+
+	remotePath, err := projectFilePath(proj.Path, c.Query("path"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.connector.Connect(proj.Server)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SSH 연결 실패: %v", err)})
+		return
+	}
+	defer client.Close()
+
+	ctx := c.Request.Context()
+	info, err := client.Stat(ctx, remotePath)
+	if err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if !info.IsDir {
+		c.JSON(http.StatusOK, gin.H{"file": info})
+		return
+	}
+
+	entries, err := client.ListDir(ctx, remotePath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path":    remotePath,
+		"entries": entries,
+	})
+}
+
+// 프로젝트 파일 내용 다운로드
+func (h *Handler) DownloadProjectFile(c *gin.Context) {
+	projectName := targetName(c)
+
+	proj, exists := h.config.GetProject(projectName)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
+
+	remotePath, err := projectFilePath(proj.Path, c.Query("path"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.connector.Connect(proj.Server)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SSH 연결 실패: %v", err)})
+		return
+	}
+	defer client.Close()
+
+	ctx := c.Request.Context()
+	info, err := client.Stat(ctx, remotePath)
+	if err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if info.IsDir {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "디렉토리는 다운로드할 수 없습니다"})
+		return
+	}
+	if info.Size > maxDownloadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("파일이 너무 큽니다 (%d bytes, 최대 %d bytes)", info.Size, int64(maxDownloadSize))})
+		return
+	}
+
+	// 메모리에 모으지 않고 바로 응답으로 보냄. Content-Length 를 넘는 내용은 보내지 않으며,
+	// 중간에 실패하면 길이가 모자란 채로 연결이 끊어져 클라이언트가 알 수 있습니다
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(remotePath)))
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	if err := client.Download(ctx, remotePath, c.Writer); err != nil {
+		if !c.Writer.Written() {
+			for _, h := range []string{"Content-Disposition", "Content-Type", "Content-Length"} {
+				c.Writer.Header().Del(h)
+			}
+			c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Printf("파일 다운로드 중단 (%s): %v\n", remotePath, err)
+	}
+}
+
+// fileErrorStatus 원격 파일 에러에 맞는 응답 코드. 없는 파일만 404 입니다
+func fileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, os.ErrPermission):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// 요청 본문을 프로젝트 파일로 업로드 (임시 파일에 쓴 뒤 교체)
+// 쿼리: path, mode(8진수, 기본 0644), uid, gid
+func (h *Handler) UploadProjectFile(c *gin.Context) {
+	projectName := targetName(c)
+
+	proj, exists := h.config.GetProject(projectName)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
+
+	remotePath, err := projectFilePath(proj.Path, c.Query("path"))
+	if err != nil || remotePath == path.Clean(proj.Path) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "업로드할 파일 경로가 올바르지 않습니다"})
+		return
+	}
+
+	opts, err := parseWriteOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.connector.Connect(proj.Server)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SSH 연결 실패: %v", err)})
+		return
+	}
+	defer client.Close()
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
+	if err := client.Upload(c.Request.Context(), remotePath, body, opts); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("파일이 너무 큽니다 (최대 %d bytes)", tooLarge.Limit)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "파일이 업로드되었습니다",
+		"path":    remotePath,
+	})
+}
+
+func parseWriteOptions(c *gin.Context) (ssh.WriteOptions, error) {
+	var opts ssh.WriteOptions
+
+	if modeStr := c.Query("mode"); modeStr != "" {
+		mode, err := strconv.ParseUint(modeStr, 8, 32)
+		if err != nil {
+			return opts, fmt.Errorf("잘못된 파일 권한입니다: %s", modeStr)
+		}
+		opts.Mode = os.FileMode(mode)
+	}
+
+	var err error
+	if opts.UID, err = optionalIntQuery(c, "uid"); err != nil {
+		return opts, err
+	}
+	if opts.GID, err = optionalIntQuery(c, "gid"); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+func optionalIntQuery(c *gin.Context, key string) (*int, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("잘못된 %s 입니다: %s", key, v)
+	}
+	return &n, nil
+}
+
+// projectFilePath 프로젝트 경로 기준 상대 경로를 원격 경로로 변환합니다.
+// 프로젝트 디렉토리 밖으로 벗어나는 경로는 거부합니다.
+func projectFilePath(projectPath, rel string) (string, error) {
+	root := path.Clean(projectPath)
+	full := path.Join(root, rel)
+	if full != root && !strings.HasPrefix(full, strings.TrimSuffix(root, "/")+"/") {
+		return "", fmt.Errorf("프로젝트 디렉토리 밖의 경로는 사용할 수 없습니다: %s", rel)
+	}
+	return full, nil
+}
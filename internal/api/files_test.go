@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestDownloadProjectFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := sshtest.NewServer(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.env"), []byte("PORT=8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "huge.log"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(filepath.Join(dir, "huge.log"), maxDownloadSize+1); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Projects: map[string]config.Project{
+		"web": {Server: srv.ConnectionConfig(), Path: dir},
+	}}
+	h := NewHandler(cfg, ssh.DirectConnector, nil, nil)
+	router := gin.New()
+	router.GET("/project/:name/files/content", h.DownloadProjectFile)
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"app.env", http.StatusOK, "PORT=8080\n"},
+		{"missing.env", http.StatusNotFound, ""},
+		{"huge.log", http.StatusRequestEntityTooLarge, ""},
+		{".", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/project/web/files/content?path="+tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.path, w.Code, tt.status, w.Body)
+			continue
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: body = %q, want %q", tt.path, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestUploadProjectFileTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := sshtest.NewServer(t)
+	dir := t.TempDir()
+
+	cfg := &config.Config{Projects: map[string]config.Project{
+		"web": {Server: srv.ConnectionConfig(), Path: dir},
+	}}
+	h := NewHandler(cfg, ssh.DirectConnector, nil, nil)
+	router := gin.New()
+	router.PUT("/project/:name/files/content", h.UploadProjectFile)
+
+	w := httptest.NewRecorder()
+	body := bytes.NewReader(make([]byte, maxUploadSize+1))
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/project/web/files/content?path=big.bin", body))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d (%s)", w.Code, http.StatusRequestEntityTooLarge, w.Body)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "big.bin")); !os.IsNotExist(err) {
+		t.Errorf("큰 파일이 업로드됨: %v", err)
+	}
+}
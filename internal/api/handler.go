@@ -1,40 +1,82 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/lambda0x63/sship/internal/audit"
 	"github.com/lambda0x63/sship/internal/config"
 	"github.com/lambda0x63/sship/internal/deploy"
+	"github.com/lambda0x63/sship/internal/secrets"
 	"github.com/lambda0x63/sship/internal/ssh"
 )
 
 type Handler struct {
 	config      *config.Config
+	connector   ssh.Connector
 	deployer    *deploy.Deployer
 	deployQueue *deploy.DeployQueue
 	upgrader    websocket.Upgrader
+	auditLog    *audit.Log
+	secrets     *secrets.Store
 }
 
-func NewHandler(cfg *config.Config) *Handler {
-	deployer := deploy.NewDeployer(cfg)
+// NewHandler auditLog 가 nil 이면 감사 로그를 남기지 않고, secretStore 가 nil 이면 시크릿 API 를 사용할 수 없습니다
+func NewHandler(cfg *config.Config, connector ssh.Connector, auditLog *audit.Log, secretStore *secrets.Store) *Handler {
+	deployer := deploy.NewDeployer(cfg, connector)
 	return &Handler{
 		config:      cfg,
+		connector:   connector,
 		deployer:    deployer,
 		deployQueue: deploy.NewDeployQueue(deployer),
+		auditLog:    auditLog,
+		secrets:     secretStore,
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true
-			},
+			CheckOrigin: sameOrigin,
 		},
 	}
 }
 
+// sameOrigin 브라우저가 보낸 Origin 이 요청한 Host 와 같을 때만 WebSocket 연결을 허용합니다.
+// 다른 사이트의 페이지가 운영자의 브라우저로 셸이나 배포 로그를 여는 것(cross-site WebSocket hijacking)을 막으며,
+// Origin 을 보내지 않는 브라우저 밖의 클라이언트는 허용합니다
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// targetName 요청이 가리키는 프로젝트. ?env=staging 을 지정하면 해당 환경 (web@staging)
+func targetName(c *gin.Context) string {
+	return config.QualifiedName(c.Param("name"), c.Query("env"))
+}
+
+// commandErrorBody 원격 명령이 실패한 경우 종료 코드와 stdout/stderr 를 함께 응답합니다
+func commandErrorBody(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	var cmdErr *ssh.CommandError
+	if errors.As(err, &cmdErr) {
+		body["result"] = cmdErr.Result
+	}
+	return body
+}
+
 type ProjectInfo struct {
 	Name        string               `json:"name"`
 	Path        string               `json:"path"`
@@ -43,10 +85,18 @@ type ProjectInfo struct {
 	Branch      string               `json:"branch"`
 	HealthCheck string               `json:"healthCheck"`
 	Server      ssh.ConnectionConfig `json:"server"`
+	ServerName  string               `json:"serverName,omitempty"`
+	// Environments 프로젝트에 정의된 환경 이름 (?env= 로 지정)
+	Environments []string `json:"environments,omitempty"`
+	Repository   string   `json:"repository,omitempty"`
+	Domain       string   `json:"domain,omitempty"`
+	Type         string   `json:"type,omitempty"`
 }
 
 type DeployRequest struct {
 	Branch string `json:"branch"`
+	// Environment 배포할 환경. ?env= 대신 지정할 수 있습니다
+	Environment string `json:"environment"`
 }
 
 type DeployResponse struct {
@@ -56,35 +106,89 @@ type DeployResponse struct {
 }
 
 type ProjectConfig struct {
-	Server        ssh.ConnectionConfig `json:"server"`
-	Path          string               `json:"path"`
-	Branch        string               `json:"branch"`
-	DockerCompose string               `json:"docker_compose"`
-	HealthCheck   string               `json:"health_check"`
+	// ServerName servers 에 정의된 서버를 쓸 때 지정. 비어있으면 Server 를 사용합니다
+	ServerName    string        `json:"server_name"`
+	Server        ServerRequest `json:"server"`
+	Path          string        `json:"path"`
+	Branch        string        `json:"branch"`
+	DockerCompose string        `json:"docker_compose"`
+	HealthCheck   string        `json:"health_check"`
+}
+
+// ServerRequest 응답에서는 숨겨지는(json:"-") 인증 정보를 요청으로 받기 위한 형식
+type ServerRequest struct {
+	ssh.ConnectionConfig
+	Password      string          `json:"password"`
+	PrivateKey    string          `json:"private_key"`
+	KeyPassphrase string          `json:"key_passphrase"`
+	SudoPassword  string          `json:"sudo_password"`
+	Jump          []ServerRequest `json:"jump"`
+}
+
+// toConnectionConfig 요청을 접속 설정으로 변환합니다.
+// 비어있는 인증 정보는 existing 값을 유지합니다.
+func (s ServerRequest) toConnectionConfig(existing ssh.ConnectionConfig) ssh.ConnectionConfig {
+	cfg := s.ConnectionConfig
+	cfg.Password = s.Password
+	cfg.PrivateKey = s.PrivateKey
+	cfg.KeyPassphrase = s.KeyPassphrase
+	cfg.Sudo.Password = s.SudoPassword
+
+	if cfg.Password == "" {
+		cfg.Password = existing.Password
+	}
+	if cfg.PrivateKey == "" {
+		cfg.PrivateKey = existing.PrivateKey
+	}
+	if cfg.KeyPassphrase == "" {
+		cfg.KeyPassphrase = existing.KeyPassphrase
+	}
+	if cfg.Sudo.Password == "" {
+		cfg.Sudo.Password = existing.Sudo.Password
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 22
+	}
+
+	cfg.Jump = nil
+	for i, jump := range s.Jump {
+		var prev ssh.ConnectionConfig
+		if i < len(existing.Jump) {
+			prev = existing.Jump[i]
+		}
+		cfg.Jump = append(cfg.Jump, jump.toConnectionConfig(prev))
+	}
+	return cfg
 }
 
 func (h *Handler) ListProjects(c *gin.Context) {
+	ctx := c.Request.Context()
 	configProjects := h.config.GetProjects()
 	projects := make([]ProjectInfo, 0, len(configProjects))
 
 	for name, proj := range configProjects {
 		info := ProjectInfo{
-			Name:        name,
-			Path:        proj.Path,
-			Branch:      proj.Branch,
-			HealthCheck: proj.HealthCheck,
-			Server:      proj.Server,
+			Name:         name,
+			Path:         proj.Path,
+			Branch:       proj.Branch,
+			HealthCheck:  proj.HealthCheck,
+			Server:       proj.Server,
+			ServerName:   proj.ServerName,
+			Environments: proj.EnvironmentNames(),
+			Repository:   proj.Repository,
+			Domain:       proj.Domain,
+			Type:         proj.Type,
 		}
 
-		client, err := ssh.NewClient(proj.Server)
+		client, err := h.connector.Connect(proj.Server)
 		if err == nil {
-			defer client.Close()
-
-			status, _ := client.CheckContainerStatus(proj.Path, proj.DockerCompose)
+			status, _ := client.CheckContainerStatus(ctx, proj.Path, proj.DockerCompose)
 			info.Status = status
 
-			lastDeployTime, _ := client.GetLastDeployTime(proj.Path)
+			lastDeployTime, _ := client.GetLastDeployTime(ctx, proj.Path)
 			info.LastDeploy = lastDeployTime
+
+			client.Close()
 		}
 
 		projects = append(projects, info)
@@ -94,7 +198,7 @@ func (h *Handler) ListProjects(c *gin.Context) {
 }
 
 func (h *Handler) GetProjectStatus(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 
 	proj, exists := h.config.GetProject(projectName)
 	if !exists {
@@ -102,29 +206,33 @@ func (h *Handler) GetProjectStatus(c *gin.Context) {
 		return
 	}
 
-	client, err := ssh.NewClient(proj.Server)
-	if err != nil {
-		fmt.Printf("SSH 연결 실패: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SSH 연결 실패: %v", err)})
-		return
-	}
-	defer client.Close()
+	ctx := c.Request.Context()
+	var status, currentCommit string
+	healthStatus := "unknown"
 
-	status, err := client.CheckContainerStatus(proj.Path, proj.DockerCompose)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+	// 조회 중 연결이 끊어지면 다시 연결해 처음부터 조회
+	err := ssh.WithReconnect(h.connector, proj.Server, func(client ssh.RemoteExecutor) error {
+		var err error
+		status, err = client.CheckContainerStatus(ctx, proj.Path, proj.DockerCompose)
+		if err != nil {
+			return err
+		}
 
-	currentCommit, _ := client.GetCurrentCommit(proj.Path)
+		currentCommit, _ = client.GetCurrentCommit(ctx, proj.Path)
 
-	healthStatus := "unknown"
-	if proj.HealthCheck != "" {
-		resp, err := http.Get(proj.HealthCheck)
-		if err == nil {
-			healthStatus = fmt.Sprintf("HTTP %d", resp.StatusCode)
-			resp.Body.Close()
+		if proj.HealthCheck != "" {
+			healthCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			if code, err := deploy.CheckHealth(healthCtx, client, proj.HealthCheck); err == nil {
+				healthStatus = fmt.Sprintf("HTTP %d", code)
+			}
+			cancel()
 		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("프로젝트 상태 조회 실패: %v\n", err)
+		c.JSON(http.StatusInternalServerError, commandErrorBody(err))
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -137,9 +245,9 @@ func (h *Handler) GetProjectStatus(c *gin.Context) {
 }
 
 func (h *Handler) GetProjectEnvironment(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 
-	envVars, err := h.deployer.GetEnvironmentVariables(projectName)
+	envVars, err := h.deployer.GetEnvironmentVariables(c.Request.Context(), projectName)
 	if err != nil {
 		fmt.Printf("GetProjectEnvironment error: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -151,6 +259,13 @@ func (h *Handler) GetProjectEnvironment(c *gin.Context) {
 		envVars = make(map[string]string)
 	}
 
+	// 배포할 때 env_file 에 써넣은 시크릿 값은 API 로 돌려주지 않음
+	for _, key := range h.config.SecretEnvKeys(projectName) {
+		if _, ok := envVars[key]; ok {
+			envVars[key] = "****"
+		}
+	}
+
 	fmt.Printf("GetProjectEnvironment: returning %d environment variables\n", len(envVars))
 
 	c.JSON(http.StatusOK, gin.H{
@@ -159,13 +274,20 @@ func (h *Handler) GetProjectEnvironment(c *gin.Context) {
 }
 
 func (h *Handler) DeployProject(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 
 	var req DeployRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청"})
 		return
 	}
+	if req.Environment != "" {
+		projectName = config.QualifiedName(c.Param("name"), req.Environment)
+	}
+	if _, exists := h.config.GetProject(projectName); !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
 
 	// 배포 큐에 추가
 	job, err := h.deployQueue.Enqueue(projectName, req.Branch)
@@ -182,26 +304,17 @@ func (h *Handler) DeployProject(c *gin.Context) {
 }
 
 func (h *Handler) GetProjectLogs(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 	lines := c.DefaultQuery("lines", "100")
 
-	proj, exists := h.config.GetProject(projectName)
-	if !exists {
+	if _, exists := h.config.GetProject(projectName); !exists {
 		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
 		return
 	}
 
-	client, err := ssh.NewClient(proj.Server)
+	logs, err := h.deployer.GetLogs(c.Request.Context(), projectName, lines)
 	if err != nil {
-		fmt.Printf("SSH 연결 실패: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SSH 연결 실패: %v", err)})
-		return
-	}
-	defer client.Close()
-
-	logs, err := client.DockerLogs(proj.Path, proj.DockerCompose, lines)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, commandErrorBody(err))
 		return
 	}
 
@@ -211,10 +324,10 @@ func (h *Handler) GetProjectLogs(c *gin.Context) {
 }
 
 func (h *Handler) RollbackProject(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 
 	go func() {
-		err := h.deployer.Rollback(projectName)
+		err := h.deployer.Rollback(context.Background(), projectName)
 		if err != nil {
 			fmt.Printf("롤백 실패: %v\n", err)
 		}
@@ -227,7 +340,7 @@ func (h *Handler) RollbackProject(c *gin.Context) {
 }
 
 func (h *Handler) StreamLogs(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 
 	_, exists := h.config.GetProject(projectName)
 	if !exists {
@@ -257,8 +370,24 @@ func (h *Handler) StreamLogs(c *gin.Context) {
 		}
 	}()
 
-	err = h.deployer.DeployWithProgress(projectName, logWriter, progressChan)
-	if err != nil {
+	// 브라우저가 연결을 끊으면 진행 중인 원격 명령도 중단
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err = h.deployer.DeployWithProgress(ctx, projectName, logWriter, progressChan)
+	if errors.Is(err, ssh.ErrConnectionLost) {
+		mu.Lock()
+		conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("[CONNECTION_LOST] %v", err)))
+		mu.Unlock()
+	} else if err != nil {
 		mu.Lock()
 		conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("[ERROR] %v", err)))
 		mu.Unlock()
@@ -285,8 +414,21 @@ func (w *WebSocketWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// projectParam 프로젝트 설정을 바꾸는 요청의 프로젝트 이름. 환경(web@staging)을 가리키면 400 을 응답하고 ok 가 false
+func projectParam(c *gin.Context) (name string, ok bool) {
+	name = c.Param("name")
+	if strings.Contains(name, config.EnvSeparator) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "프로젝트 이름에 '" + config.EnvSeparator + "' 를 쓸 수 없습니다"})
+		return "", false
+	}
+	return name, true
+}
+
 func (h *Handler) AddProject(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName, ok := projectParam(c)
+	if !ok {
+		return
+	}
 
 	var projectConfig ProjectConfig
 	if err := c.ShouldBindJSON(&projectConfig); err != nil {
@@ -294,8 +436,14 @@ func (h *Handler) AddProject(c *gin.Context) {
 		return
 	}
 
+	serverName, server, ok := h.projectServer(c, projectConfig, config.Project{})
+	if !ok {
+		return
+	}
+
 	h.config.SetProject(projectName, config.Project{
-		Server:        projectConfig.Server,
+		Server:        server,
+		ServerName:    serverName,
 		Path:          projectConfig.Path,
 		Branch:        projectConfig.Branch,
 		DockerCompose: projectConfig.DockerCompose,
@@ -314,26 +462,43 @@ func (h *Handler) AddProject(c *gin.Context) {
 }
 
 func (h *Handler) UpdateProject(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName, ok := projectParam(c)
+	if !ok {
+		return
+	}
 
-	if _, exists := h.config.GetProject(projectName); !exists {
+	existing, exists := h.config.GetProject(projectName)
+	if !exists {
 		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
 		return
 	}
 
-	var projectConfig ProjectConfig
+	// 요청에 없는 필드는 기존 값을 유지
+	projectConfig := ProjectConfig{
+		Path:          existing.Path,
+		Branch:        existing.Branch,
+		DockerCompose: existing.DockerCompose,
+		HealthCheck:   existing.HealthCheck,
+	}
 	if err := c.ShouldBindJSON(&projectConfig); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식"})
 		return
 	}
 
-	h.config.SetProject(projectName, config.Project{
-		Server:        projectConfig.Server,
-		Path:          projectConfig.Path,
-		Branch:        projectConfig.Branch,
-		DockerCompose: projectConfig.DockerCompose,
-		HealthCheck:   projectConfig.HealthCheck,
-	})
+	serverName, server, ok := h.projectServer(c, projectConfig, existing)
+	if !ok {
+		return
+	}
+
+	// API 로 노출하지 않는 설정(timeouts, files, hooks, environments 등)은 기존 프로젝트에서 그대로 가져옵니다
+	project := existing
+	project.Server = server
+	project.ServerName = serverName
+	project.Path = projectConfig.Path
+	project.Branch = projectConfig.Branch
+	project.DockerCompose = projectConfig.DockerCompose
+	project.HealthCheck = projectConfig.HealthCheck
+	h.config.SetProject(projectName, project)
 
 	if err := h.config.Save(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "설정 저장 실패"})
@@ -347,7 +512,10 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 }
 
 func (h *Handler) DeleteProject(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName, ok := projectParam(c)
+	if !ok {
+		return
+	}
 
 	if _, exists := h.config.GetProject(projectName); !exists {
 		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
@@ -368,13 +536,14 @@ func (h *Handler) DeleteProject(c *gin.Context) {
 }
 
 func (h *Handler) TestConnection(c *gin.Context) {
-	var server ssh.ConnectionConfig
-	if err := c.ShouldBindJSON(&server); err != nil {
+	var req ServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식"})
 		return
 	}
 
-	client, err := ssh.NewClient(server)
+	// 운영자가 직접 요청한 연결 테스트에서만 처음 보는 호스트 키를 등록
+	client, err := ssh.NewClientPinningHostKey(req.toConnectionConfig(ssh.ConnectionConfig{}))
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
 			"success": false,
@@ -384,7 +553,7 @@ func (h *Handler) TestConnection(c *gin.Context) {
 	}
 	defer client.Close()
 
-	if err := client.CheckConnection(); err != nil {
+	if err := client.CheckConnection(c.Request.Context()); err != nil {
 		c.JSON(http.StatusOK, gin.H{
 			"success": false,
 			"message": fmt.Sprintf("연결 테스트 실패: %v", err),
@@ -392,19 +561,131 @@ func (h *Handler) TestConnection(c *gin.Context) {
 		return
 	}
 
+	message := "연결 성공"
+	if client.HostKeyPinned() {
+		message = "연결 성공 (새 호스트 키 등록됨)"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"message":         message,
+		"host_key":        client.HostKeyFingerprint(),
+		"host_key_pinned": client.HostKeyPinned(),
+	})
+}
+
+type PinHostKeyRequest struct {
+	Host        string `json:"host"`
+	Port        int    `json:"port"`
+	Project     string `json:"project"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// 등록된 호스트 키 목록 조회
+func (h *Handler) ListKnownHosts(c *gin.Context) {
+	c.JSON(http.StatusOK, ssh.HostKeys().List())
+}
+
+// 서버 재구축 등으로 바뀐 호스트 키를 다시 등록
+// fingerprint 를 함께 보내면 서버가 제시한 키와 일치할 때만 등록합니다
+func (h *Handler) PinHostKey(c *gin.Context) {
+	var req PinHostKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식"})
+		return
+	}
+
+	// 프로젝트를 지정하면 점프 호스트를 포함한 프로젝트의 서버 설정을 사용
+	server := ssh.ConnectionConfig{Host: req.Host, Port: req.Port}
+	if req.Project != "" {
+		proj, exists := h.config.GetProject(req.Project)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+			return
+		}
+		server = proj.Server
+	}
+	if server.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "host 또는 project 가 필요합니다"})
+		return
+	}
+	if server.Port == 0 {
+		server.Port = 22
+	}
+
+	key, err := ssh.FetchHostKey(server)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 서버가 제시한 키를 그대로 믿지 않도록 운영자가 확인한 지문과 일치할 때만 등록
+	fingerprint := ssh.FingerprintSHA256(key)
+	if req.Fingerprint == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    "fingerprint 가 필요합니다. 서버 콘솔에서 ssh-keygen -lf 로 확인한 지문을 지정하세요",
+			"host_key": fingerprint,
+		})
+		return
+	}
+	if req.Fingerprint != fingerprint {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "서버가 제시한 호스트 키가 요청한 지문과 다릅니다",
+			"host_key": fingerprint,
+		})
+		return
+	}
+
+	if err := ssh.HostKeys().Pin(server.Host, server.Port, key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "호스트 키가 등록되었습니다",
+		"host_key": fingerprint,
+	})
+}
+
+func (h *Handler) DeleteKnownHost(c *gin.Context) {
+	host := c.Query("host")
+	port := 22
+	if portStr := c.Query("port"); portStr != "" {
+		if _, err := fmt.Sscanf(portStr, "%d", &port); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 포트 번호"})
+			return
+		}
+	}
+
+	if err := ssh.HostKeys().Remove(host, port); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "연결 성공",
+		"message": "호스트 키가 삭제되었습니다",
 	})
 }
 
+// SSH 연결 풀 상태 조회
+func (h *Handler) GetPoolStats(c *gin.Context) {
+	pool, ok := h.connector.(*ssh.Pool)
+	if !ok {
+		c.JSON(http.StatusOK, []ssh.PoolStats{})
+		return
+	}
+	c.JSON(http.StatusOK, pool.Stats())
+}
+
 // 배포 히스토리 조회
 func (h *Handler) GetDeployHistory(c *gin.Context) {
-	projectName := c.Param("name")
+	projectName := targetName(c)
 	limit := 10
 
 	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := fmt.Sscanf(limitStr, "%d", &limit); err == nil && l > 0 {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
 			limit = l
 		}
 	}
@@ -413,6 +694,19 @@ func (h *Handler) GetDeployHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, history)
 }
 
+// 대기 중이거나 실행 중인 배포 작업 취소
+func (h *Handler) CancelDeploy(c *gin.Context) {
+	if err := h.deployQueue.Cancel(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "배포 취소를 요청했습니다",
+	})
+}
+
 // 현재 진행 중인 배포 작업 조회
 func (h *Handler) GetActiveJobs(c *gin.Context) {
 	jobs := h.deployQueue.GetActiveJobs()
@@ -441,3 +735,34 @@ func (h *Handler) StreamDeployEvents(c *gin.Context) {
 		}
 	}
 }
+
+// ConfigReloaded 설정 파일을 다시 로드한 결과를 로그에 남기고 이벤트 구독자에게 알립니다
+func (h *Handler) ConfigReloaded(change config.Change, err error) {
+	if err != nil {
+		fmt.Printf("⚠️ 설정 다시 로드 실패 (기존 설정 유지): %v\n", err)
+		h.deployQueue.Notify(deploy.EventConfigInvalid, err.Error())
+		return
+	}
+	fmt.Printf("🔄 설정 다시 로드: %s\n", change)
+	h.deployQueue.Notify(deploy.EventConfigChanged, change.String())
+}
+
+// ValidateConfig 요청 본문으로 받은 sship.yaml 내용을 검증하고 문제 위치를 반환합니다
+func (h *Handler) ValidateConfig(c *gin.Context) {
+	data, err := c.GetRawData()
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "검증할 설정 내용이 필요합니다"})
+		return
+	}
+
+	err = config.Validate(data)
+	var errs config.ValidationErrors
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"valid": true, "errors": config.ValidationErrors{}})
+	case errors.As(err, &errs):
+		c.JSON(http.StatusOK, gin.H{"valid": false, "errors": errs})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://localhost:9999", true},
+		{"http://LOCALHOST:9999", true},
+		{"https://evil.example.com", false},
+		{"http://localhost:8080", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v1/ws/shell/web", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := sameOrigin(r); got != tt.want {
+			t.Errorf("sameOrigin(Origin: %q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateProjectKeepsSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    branch: main
+    env_file: .env.production
+    port: 8080
+    timeouts:
+      build: 20m
+    files:
+      - source: ./web.env
+        dest: .env
+        mode: "0600"
+    repository: git@github.com:example/web.git
+    domain: web.example.com
+    type: nextjs
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	h := NewHandler(cfg, nil, nil, nil)
+	router := gin.New()
+	router.PATCH("/project/:name", h.UpdateProject)
+
+	w := httptest.NewRecorder()
+	body := `{"server": {"host": "10.0.0.1"}, "branch": "develop"}`
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/project/web", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateProject = %d %s", w.Code, w.Body)
+	}
+
+	// 저장한 파일을 다시 읽어도 요청에 없던 설정이 남아있어야 함
+	reloaded, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	web, _ := reloaded.GetProject("web")
+	if web.Branch != "develop" || web.Path != "/srv/web" {
+		t.Errorf("branch, path = %q, %q", web.Branch, web.Path)
+	}
+	if web.Timeouts.Build != 20*time.Minute {
+		t.Errorf("timeouts = %+v", web.Timeouts)
+	}
+	if web.EnvFile != ".env.production" || web.Port != 8080 {
+		t.Errorf("env_file, port = %q, %d", web.EnvFile, web.Port)
+	}
+	if len(web.Files) != 1 || web.Files[0] != (config.FileSpec{Source: "./web.env", Dest: ".env", Mode: "0600"}) {
+		t.Errorf("files = %+v", web.Files)
+	}
+	if web.Repository != "git@github.com:example/web.git" || web.Domain != "web.example.com" || web.Type != "nextjs" {
+		t.Errorf("repository, domain, type = %q, %q, %q", web.Repository, web.Domain, web.Type)
+	}
+}
+
+type testSecrets map[string]string
+
+func (s testSecrets) Get(name string) (string, bool) {
+	value, ok := s[name]
+	return value, ok
+}
+
+func TestGetProjectEnvironmentMasksSecrets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := sshtest.NewServer(t)
+	// 배포할 때 sship 이 써넣은 env_file
+	srv.Handle("cat", sshtest.Response{Stdout: "API_TOKEN='tok-s3cret'\nDATABASE_URL='postgres://app:s3cret@db/app'\nLOG_LEVEL='info'\n"})
+
+	server := srv.ConnectionConfig()
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := fmt.Sprintf(`projects:
+  web:
+    server:
+      host: %s
+      port: %d
+      user: %s
+      password: %s
+    path: /srv/web
+    env:
+      DATABASE_URL: secret:web-db
+      LOG_LEVEL: info
+    environments:
+      staging:
+        env:
+          API_TOKEN: secret:staging-token
+`, server.Host, server.Port, server.User, server.Password)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config.UseSecrets(testSecrets{"web-db": "postgres://app:s3cret@db/app", "staging-token": "tok-s3cret"})
+	defer config.UseSecrets(nil)
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	h := NewHandler(cfg, ssh.DirectConnector, nil, nil)
+	router := gin.New()
+	router.GET("/project/:name/environment", h.GetProjectEnvironment)
+
+	for _, target := range []string{"/project/web/environment", "/project/web/environment?env=staging"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: GetProjectEnvironment = %d %s", target, w.Code, w.Body)
+		}
+		if strings.Contains(w.Body.String(), "s3cret") {
+			t.Errorf("%s: 시크릿 값이 응답에 노출됨: %s", target, w.Body)
+		}
+		if !strings.Contains(w.Body.String(), `"LOG_LEVEL":"info"`) {
+			t.Errorf("%s: 일반 변수가 응답에 없음: %s", target, w.Body)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/config"
+)
+
+// ImportLegacyConfig sship.yaml 옆의 configs/projects/*.yaml 을 sship.yaml 로 가져오고 항목별 결과를 반환합니다.
+// 다른 디렉토리는 서버의 파일을 읽을 수 있게 되므로 API 로는 지정할 수 없습니다 (-import-legacy 사용).
+// dry_run 이면 설정을 바꾸지 않고 바뀔 내용만 보고합니다
+func (h *Handler) ImportLegacyConfig(c *gin.Context) {
+	var req config.ImportOptions
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식"})
+		return
+	}
+
+	report, err := h.config.ImportLegacy(h.config.LegacyDir(), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, os.ErrNotExist) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+
+	if change := report.Change(); !change.Empty() {
+		h.ConfigReloaded(change, nil)
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/config"
+)
+
+func TestImportLegacyConfigDir(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "configs", "projects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `project:
+  name: web
+  path: /srv/web
+  server:
+    host: 10.0.0.1
+`
+	if err := os.WriteFile(filepath.Join(dir, "configs", "projects", "web.yaml"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "sship.yaml")
+	if err := os.WriteFile(path, []byte("projects: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	h := NewHandler(cfg, nil, nil, nil)
+	router := gin.New()
+	router.POST("/config/import-legacy", h.ImportLegacyConfig)
+
+	// 요청의 dir 는 무시하고 설정 파일 옆의 configs 만 읽어야 함
+	w := httptest.NewRecorder()
+	body := `{"dir": "/etc", "dry_run": true}`
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/config/import-legacy", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ImportLegacyConfig = %d %s", w.Code, w.Body)
+	}
+	var report config.ImportReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Dir != filepath.Join(dir, "configs") || len(report.Results) != 1 || report.Results[0].Project != "web" {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/config"
+)
+
+// ListConfigRevisions 보관된 sship.yaml 버전 목록 (오래된 순)
+func (h *Handler) ListConfigRevisions(c *gin.Context) {
+	revisions, err := h.config.Revisions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetConfigRevision 버전의 파일 내용 (YAML)
+func (h *Handler) GetConfigRevision(c *gin.Context) {
+	id, ok := revisionID(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	data, err := h.config.RevisionContent(id)
+	if err != nil {
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+}
+
+// DiffConfigRevisions ?from=3&to=5 두 버전의 unified diff. to 를 생략하면 현재 설정 파일과 비교합니다
+func (h *Handler) DiffConfigRevisions(c *gin.Context) {
+	from, ok := revisionID(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := revisionID(c, c.DefaultQuery("to", "0"))
+	if !ok {
+		return
+	}
+
+	diff, err := h.config.Diff(from, to)
+	if err != nil {
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from": from,
+		"to":   to,
+		"diff": diff,
+	})
+}
+
+// RestoreConfigRevision 보관된 버전으로 설정을 되돌리고 다시 로드합니다
+func (h *Handler) RestoreConfigRevision(c *gin.Context) {
+	id, ok := revisionID(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	change, err := h.config.Restore(id)
+	var errs config.ValidationErrors
+	switch {
+	case errors.As(err, &errs):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "검증에 실패한 버전입니다", "errors": errs})
+		return
+	case err != nil:
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.ConfigReloaded(change, nil)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "설정을 버전 " + strconv.Itoa(id) + " 으로 되돌렸습니다",
+		"change":  change,
+	})
+}
+
+// revisionID 버전 번호를 읽습니다. 0 은 현재 설정 파일을 뜻합니다. 잘못된 값이면 400 을 응답하고 ok 가 false
+func revisionID(c *gin.Context, value string) (int, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 버전 번호입니다: " + value})
+		return 0, false
+	}
+	return id, true
+}
+
+func revisionErrorStatus(err error) int {
+	var refErr *config.ReferenceError
+	switch {
+	case errors.Is(err, config.ErrRevisionNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &refErr):
+		// 버전에 있는 secret:, ${VAR} 등을 지금은 해석할 수 없는 경우
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
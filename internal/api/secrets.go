@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/secrets"
+)
+
+// SecretRequest 시크릿 생성/교체 요청. 값은 응답으로 돌려주지 않습니다
+type SecretRequest struct {
+	Value string `json:"value"`
+}
+
+// secretStore 시크릿 저장소가 없으면 503 을 응답하고 nil 을 반환합니다
+func (h *Handler) secretStore(c *gin.Context) *secrets.Store {
+	if h.secrets == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "시크릿 저장소가 설정되지 않았습니다 (" + secrets.MasterKeyEnv + " 또는 -master-key-file 필요)"})
+		return nil
+	}
+	return h.secrets
+}
+
+// ListSecrets 시크릿 이름과 버전, 변경 시각 (값 제외)
+func (h *Handler) ListSecrets(c *gin.Context) {
+	store := h.secretStore(c)
+	if store == nil {
+		return
+	}
+	c.JSON(http.StatusOK, store.List())
+}
+
+// PutSecret 시크릿을 만들거나 새 값으로 교체합니다. 이 시크릿을 참조하는 프로젝트나 서버가 있으면 설정을 다시 로드합니다
+func (h *Handler) PutSecret(c *gin.Context) {
+	store := h.secretStore(c)
+	if store == nil {
+		return
+	}
+	name := c.Param("name")
+
+	var req SecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식"})
+		return
+	}
+
+	info, err := store.Set(name, req.Value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ref := "secret:" + name
+	if len(h.config.ProjectsUsingReference(ref)) > 0 || len(h.config.ServersUsingReference(ref)) > 0 {
+		h.ConfigReloaded(h.config.Reload())
+	}
+
+	status := http.StatusOK
+	if info.Version == 1 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, info)
+}
+
+// DeleteSecret 프로젝트나 서버가 참조 중인 시크릿은 삭제하지 않습니다
+func (h *Handler) DeleteSecret(c *gin.Context) {
+	store := h.secretStore(c)
+	if store == nil {
+		return
+	}
+	name := c.Param("name")
+
+	// 프로젝트가 없는 서버라도 참조가 남아 있으면 다음 로드가 실패하므로 삭제하지 않음
+	ref := "secret:" + name
+	projects, servers := h.config.ProjectsUsingReference(ref), h.config.ServersUsingReference(ref)
+	if len(projects) > 0 || len(servers) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "프로젝트나 서버에서 사용 중인 시크릿입니다",
+			"projects": projects,
+			"servers":  servers,
+		})
+		return
+	}
+
+	if err := store.Delete(name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, secrets.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
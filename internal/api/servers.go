@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+)
+
+// ServerInfo 공유 서버와 이 서버를 참조하는 프로젝트
+type ServerInfo struct {
+	Name     string               `json:"name"`
+	Server   ssh.ConnectionConfig `json:"server"`
+	Projects []string             `json:"projects"`
+}
+
+func (h *Handler) serverInfo(name string, server ssh.ConnectionConfig) ServerInfo {
+	projects := h.config.ProjectsUsingServer(name)
+	if projects == nil {
+		projects = []string{}
+	}
+	return ServerInfo{Name: name, Server: server, Projects: projects}
+}
+
+// projectServer 프로젝트 요청의 server_name 이나 인라인 server 를 프로젝트의 서버 설정으로 바꿉니다.
+// 정의되지 않은 서버 이름이면 400 을 응답하고 ok 가 false
+func (h *Handler) projectServer(c *gin.Context, req ProjectConfig, existing config.Project) (name string, server ssh.ConnectionConfig, ok bool) {
+	if req.ServerName == "" {
+		return existing.ServerName, req.Server.toConnectionConfig(existing.Server), true
+	}
+	if _, exists := h.config.GetServer(req.ServerName); !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "정의되지 않은 서버입니다: " + req.ServerName})
+		return "", ssh.ConnectionConfig{}, false
+	}
+	return req.ServerName, ssh.ConnectionConfig{}, true
+}
+
+func (h *Handler) ListServers(c *gin.Context) {
+	servers := h.config.GetServers()
+	infos := make([]ServerInfo, 0, len(servers))
+	for name, server := range servers {
+		infos = append(infos, h.serverInfo(name, server))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	c.JSON(http.StatusOK, infos)
+}
+
+func (h *Handler) GetServer(c *gin.Context) {
+	name := c.Param("name")
+	server, exists := h.config.GetServer(name)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "서버를 찾을 수 없습니다"})
+		return
+	}
+	c.JSON(http.StatusOK, h.serverInfo(name, server))
+}
+
+// PutServer 서버를 만들거나 수정합니다. 비어있는 인증 정보는 기존 값을 유지하고, 참조하는 모든 프로젝트에 반영됩니다
+func (h *Handler) PutServer(c *gin.Context) {
+	name := c.Param("name")
+	if err := config.ValidateServerName(name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req ServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식"})
+		return
+	}
+	if req.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "서버 호스트가 설정되지 않았습니다"})
+		return
+	}
+
+	existing, exists := h.config.GetServer(name)
+	server := req.toConnectionConfig(existing)
+	h.config.SetServer(name, server)
+
+	if err := h.config.Save(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "설정 저장 실패"})
+		return
+	}
+
+	status := http.StatusOK
+	if !exists {
+		status = http.StatusCreated
+	}
+	c.JSON(status, h.serverInfo(name, server))
+}
+
+// DeleteServer 프로젝트가 참조 중인 서버는 삭제하지 않습니다
+func (h *Handler) DeleteServer(c *gin.Context) {
+	name := c.Param("name")
+
+	if projects := h.config.ProjectsUsingServer(name); len(projects) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "프로젝트에서 사용 중인 서버입니다",
+			"projects": projects,
+		})
+		return
+	}
+
+	if err := h.config.DeleteServer(name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, config.ErrServerNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, config.ErrServerInUse) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.config.Save(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "설정 저장 실패"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
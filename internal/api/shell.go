@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/lambda0x63/sship/internal/audit"
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+)
+
+// ShellMessage 브라우저 터미널이 보내는 메시지.
+// type 이 "input" 이면 data 를 셸 입력으로, "resize" 이면 cols/rows 로 창 크기를 바꿉니다.
+// 서버는 셸 출력을 바이너리 메시지로 보냅니다.
+type ShellMessage struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Cols int    `json:"cols,omitempty"`
+	Rows int    `json:"rows,omitempty"`
+}
+
+// terminalSession WebSocket 과 연결할 PTY 세션
+type terminalSession struct {
+	kind      string // 세션 ID 접두어 (shell, exec)
+	project   string
+	proj      config.Project
+	event     audit.Event
+	openType  string
+	closeType string
+	open      func(ctx context.Context, client ssh.RemoteExecutor, opts ssh.ShellOptions) (*ssh.Shell, error)
+}
+
+// 프로젝트 경로에서 시작하는 대화형 셸 (WebSocket)
+// 쿼리: cols, rows (초기 터미널 크기)
+func (h *Handler) OpenShell(c *gin.Context) {
+	projectName := targetName(c)
+
+	proj, exists := h.config.GetProject(projectName)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
+
+	h.serveTerminal(c, terminalSession{
+		kind:      "shell",
+		project:   projectName,
+		proj:      proj,
+		event:     audit.Event{Dir: proj.Path},
+		openType:  audit.EventShellOpen,
+		closeType: audit.EventShellClose,
+		open: func(ctx context.Context, client ssh.RemoteExecutor, opts ssh.ShellOptions) (*ssh.Shell, error) {
+			opts.Dir = proj.Path
+			return client.OpenShell(ctx, opts)
+		},
+	})
+}
+
+// 프로젝트 compose 서비스 목록
+func (h *Handler) ListComposeServices(c *gin.Context) {
+	projectName := targetName(c)
+
+	proj, exists := h.config.GetProject(projectName)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
+
+	client, err := h.connector.Connect(proj.Server)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("SSH 연결 실패: %v", err)})
+		return
+	}
+	defer client.Close()
+
+	services, err := client.ComposeServices(c.Request.Context(), proj.Path, proj.DockerCompose)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, commandErrorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"services": services})
+}
+
+// 서비스 컨테이너 안에서 대화형 명령 실행 (WebSocket, docker compose exec)
+// 쿼리: cmd (기본 sh, 공백으로 인자 구분), cols, rows
+func (h *Handler) ExecService(c *gin.Context) {
+	projectName := targetName(c)
+	service := c.Param("service")
+
+	proj, exists := h.config.GetProject(projectName)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "프로젝트를 찾을 수 없습니다"})
+		return
+	}
+
+	command := strings.Fields(c.DefaultQuery("cmd", "sh"))
+
+	h.serveTerminal(c, terminalSession{
+		kind:      "exec",
+		project:   projectName,
+		proj:      proj,
+		event:     audit.Event{Dir: proj.Path, Service: service, Command: strings.Join(command, " ")},
+		openType:  audit.EventExecOpen,
+		closeType: audit.EventExecClose,
+		open: func(ctx context.Context, client ssh.RemoteExecutor, opts ssh.ShellOptions) (*ssh.Shell, error) {
+			return client.ComposeExec(ctx, proj.Path, proj.DockerCompose, service, command, opts)
+		},
+	})
+}
+
+// serveTerminal WebSocket 으로 업그레이드한 뒤 PTY 세션과 양방향으로 연결합니다
+func (h *Handler) serveTerminal(c *gin.Context, ts terminalSession) {
+	cols, _ := strconv.Atoi(c.Query("cols"))
+	rows, _ := strconv.Atoi(c.Query("rows"))
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var mu sync.Mutex
+	writeText := func(msg string) {
+		mu.Lock()
+		conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		mu.Unlock()
+	}
+
+	event := ts.event
+	event.SessionID = fmt.Sprintf("%s-%s-%d", ts.kind, ts.project, time.Now().UnixNano())
+	event.Project = ts.project
+	event.Server = fmt.Sprintf("%s@%s:%d", ts.proj.Server.User, ts.proj.Server.Host, ts.proj.Server.Port)
+	event.Remote = c.ClientIP()
+	event.UserAgent = c.Request.UserAgent()
+
+	client, err := h.connector.Connect(ts.proj.Server)
+	if err != nil {
+		h.recordAudit(event, ts.openType, err)
+		writeText(fmt.Sprintf("[ERROR] SSH 연결 실패: %v", err))
+		return
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shell, err := ts.open(ctx, client, ssh.ShellOptions{Cols: cols, Rows: rows})
+	if err != nil {
+		h.recordAudit(event, ts.openType, err)
+		writeText(fmt.Sprintf("[ERROR] %v", err))
+		return
+	}
+	defer shell.Close()
+
+	opened := time.Now()
+	h.recordAudit(event, ts.openType, nil)
+	defer func() {
+		event.Duration = time.Since(opened).Round(time.Second).String()
+		h.recordAudit(event, ts.closeType, nil)
+	}()
+
+	// 셸 출력 → 브라우저
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := shell.Stdout.Read(buf)
+			if n > 0 {
+				mu.Lock()
+				werr := conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+				mu.Unlock()
+				if werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// 브라우저 입력 → 셸. 브라우저가 연결을 끊으면 셸도 종료
+	go func() {
+		defer cancel()
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				shell.Stdin.Write(data)
+				continue
+			}
+
+			var msg ShellMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+			switch msg.Type {
+			case "input":
+				shell.Stdin.Write([]byte(msg.Data))
+			case "resize":
+				shell.Resize(msg.Cols, msg.Rows)
+			}
+		}
+	}()
+
+	select {
+	case <-outputDone:
+		// 셸이 종료됨 (exit 등)
+		shell.Wait()
+		writeText("[CLOSED] 셸 세션이 종료되었습니다")
+	case <-ctx.Done():
+	}
+}
+
+// 감사 로그 조회
+func (h *Handler) ListAuditLog(c *gin.Context) {
+	if h.auditLog == nil {
+		c.JSON(http.StatusOK, gin.H{"events": []audit.Event{}})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	events, err := h.auditLog.Recent(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+func (h *Handler) recordAudit(event audit.Event, eventType string, cause error) {
+	if h.auditLog == nil {
+		return
+	}
+	event.Type = eventType
+	if cause != nil {
+		event.Error = cause.Error()
+	}
+	if err := h.auditLog.Record(event); err != nil {
+		fmt.Printf("감사 로그 기록 실패: %v\n", err)
+	}
+}
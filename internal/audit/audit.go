@@ -0,0 +1,106 @@
+// Package audit 원격 서버에 대한 대화형 접근 기록을 JSON Lines 파일로 남깁니다.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	EventShellOpen  = "shell.open"
+	EventShellClose = "shell.close"
+	EventExecOpen   = "exec.open"
+	EventExecClose  = "exec.close"
+)
+
+// Event 감사 로그 항목
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	SessionID string    `json:"session_id"`
+	Project   string    `json:"project"`
+	Server    string    `json:"server"`
+	Dir       string    `json:"dir,omitempty"`
+	Service   string    `json:"service,omitempty"`
+	Command   string    `json:"command,omitempty"`
+	Remote    string    `json:"remote,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	// Duration 세션 유지 시간 (*.close 에만 기록)
+	Duration string `json:"duration,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Log 파일에 추가만 하는 감사 로그
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog path 에 감사 로그를 기록합니다. 파일이 없으면 만듭니다.
+func NewLog(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("감사 로그 파일 열기 실패: %v", err)
+	}
+	f.Close()
+	return &Log{path: path}, nil
+}
+
+// Record 항목을 한 줄로 기록합니다. Time 이 비어 있으면 현재 시각을 사용합니다.
+func (l *Log) Record(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("감사 로그 기록 실패: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("감사 로그 기록 실패: %v", err)
+	}
+	return nil
+}
+
+// Recent 최근 항목을 최신순으로 최대 limit 개 반환합니다
+func (l *Log) Recent(limit int) ([]Event, error) {
+	l.mu.Lock()
+	data, err := os.ReadFile(l.path)
+	l.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("감사 로그 읽기 실패: %v", err)
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		// 손상된 줄은 건너뜀
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			events = append(events, e)
+		}
+	}
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log, err := NewLog(path)
+	if err != nil {
+		t.Fatalf("NewLog() error = %v", err)
+	}
+
+	for _, typ := range []string{EventShellOpen, EventShellClose, EventShellOpen} {
+		if err := log.Record(Event{Type: typ, Project: "app"}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	// 손상된 줄은 무시되어야 함
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString("{broken\n")
+	f.Close()
+
+	events, err := log.Recent(2)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Type != EventShellOpen || events[1].Type != EventShellClose {
+		t.Fatalf("Recent(2) = %+v", events)
+	}
+	if events[0].Time.IsZero() {
+		t.Error("Time 이 기록되지 않음")
+	}
+}
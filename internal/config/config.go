@@ -1,11 +1,16 @@
 package config
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 
 	"sync"
+	"time"
 
 	"github.com/lambda0x63/sship/internal/ssh"
 	"gopkg.in/yaml.v3"
@@ -32,41 +37,178 @@ type ProjectConfig struct {
 }
 
 type Project struct {
+	// Server servers 에 정의된 서버를 쓰면 GetProject 가 ServerName 의 설정으로 채웁니다
 	Server        ssh.ConnectionConfig `yaml:"server"`
+	ServerName    string               `yaml:"-"`
 	Path          string               `yaml:"path"`
 	Branch        string               `yaml:"branch"`
 	DockerCompose string               `yaml:"docker_compose"`
 	HealthCheck   string               `yaml:"health_check"`
 	EnvFile       string               `yaml:"env_file"`
 	Port          int                  `yaml:"port"`
+	Timeouts      StepTimeouts         `yaml:"timeouts,omitempty"`
+	Files         []FileSpec           `yaml:"files,omitempty"`
+	// Env 배포할 때 env_file 로 써서 앱에 전달할 환경변수. 값에 secret: 참조를 쓸 수 있습니다
+	Env   map[string]string `yaml:"env,omitempty"`
+	Hooks Hooks             `yaml:"hooks,omitempty"`
+	// 이전 configs/projects 형식에서 가져온 정보 (배포에는 사용하지 않음)
+	Repository string `yaml:"repository,omitempty"`
+	Domain     string `yaml:"domain,omitempty"`
+	Type       string `yaml:"type,omitempty"`
+	// Environments 이 설정을 상속하는 환경 (staging, production 등)
+	Environments map[string]Environment `yaml:"environments,omitempty"`
+}
+
+// FileSpec 배포 시 sship 호스트에서 서버로 올릴 파일 (compose, env, 설정 파일 등)
+type FileSpec struct {
+	Source string `yaml:"source" json:"source"`
+	// 원격 경로. 상대 경로면 프로젝트 path 기준
+	Dest string `yaml:"dest" json:"dest"`
+	// 8진수 권한 ("0600"). 비어있으면 0644
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	UID  *int   `yaml:"uid,omitempty" json:"uid,omitempty"`
+	GID  *int   `yaml:"gid,omitempty" json:"gid,omitempty"`
+}
+
+// RemotePath 프로젝트 경로를 기준으로 한 원격 경로
+func (f FileSpec) RemotePath(projectPath string) string {
+	if path.IsAbs(f.Dest) {
+		return path.Clean(f.Dest)
+	}
+	return path.Join(projectPath, f.Dest)
+}
+
+// DefaultEnvFile env 를 지정하고 env_file 을 비워둔 경우 쓰는 파일
+const DefaultEnvFile = ".env"
+
+// EnvFilePath env 를 써넣을 원격 경로. 상대 경로면 프로젝트 path 기준
+func (p Project) EnvFilePath() string {
+	file := p.EnvFile
+	if file == "" {
+		file = DefaultEnvFile
+	}
+	if path.IsAbs(file) {
+		return path.Clean(file)
+	}
+	return path.Join(p.Path, file)
+}
+
+// FileMode mode 문자열을 파일 권한으로 변환합니다
+func (f FileSpec) FileMode() (os.FileMode, error) {
+	if f.Mode == "" {
+		return 0644, nil
+	}
+	mode, err := strconv.ParseUint(f.Mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("잘못된 파일 권한입니다 (%s): %v", f.Mode, err)
+	}
+	return os.FileMode(mode), nil
+}
+
+// StepTimeouts 배포 단계별 제한 시간 ("5m", "90s" 형식). 0 이면 기본값을 사용합니다
+type StepTimeouts struct {
+	Pull   time.Duration `yaml:"pull,omitempty" json:"pull,omitempty"`
+	Build  time.Duration `yaml:"build,omitempty" json:"build,omitempty"`
+	Health time.Duration `yaml:"health,omitempty" json:"health,omitempty"`
 }
 
 type Config struct {
-	Projects map[string]Project `yaml:"projects"`
+	// Servers 여러 프로젝트가 이름으로 참조하는 공유 서버 접속 설정
+	Servers  map[string]ssh.ConnectionConfig `yaml:"servers,omitempty"`
+	Projects map[string]Project              `yaml:"projects"`
 	mu       sync.RWMutex
 	filePath string
+	// 마지막으로 읽거나 저장한 파일 내용의 해시 (자체 저장으로 인한 다시 로드 방지)
+	fileHash [sha256.Size]byte
+	// 프로젝트별, 서버별 ${ENV}, file: 참조 (Save 시 보존)
+	refs       map[string]map[string]reference
+	serverRefs map[string]map[string]reference
 }
 
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("설정 파일을 읽을 수 없습니다: %v", err)
+		return nil, fmt.Errorf("설정 파일을 읽을 수 없습니다: %w", err)
+	}
+	if err := Validate(data); err != nil {
+		return nil, fmt.Errorf("설정 파일 검증 실패 (%s):\n%w", path, err)
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		return nil, err
 	}
+	config.filePath = path
+	config.fileHash = sha256.Sum256(data)
+	return config, nil
+}
 
+// parseConfig 설정 파일 내용을 파싱하고 기본값을 채웁니다
+func parseConfig(data []byte) (*Config, error) {
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("설정 파일 파싱 실패: %v", err)
 	}
 
-	config.filePath = path
-
 	if config.Projects == nil {
 		config.Projects = make(map[string]Project)
 	}
+	if config.Servers == nil {
+		config.Servers = make(map[string]ssh.ConnectionConfig)
+	}
+
+	config.serverRefs = make(map[string]map[string]reference)
+	for name, server := range config.Servers {
+		refs, err := resolveReferences(&server)
+		if err != nil {
+			return nil, fmt.Errorf("서버 %s: %w", name, err)
+		}
+		if len(refs) > 0 {
+			config.serverRefs[name] = refs
+		}
+		if server.Port == 0 {
+			server.Port = 22
+		}
+		config.Servers[name] = server
+	}
 
-	for name, proj := range config.Projects {
-		if proj.Server.Port == 0 {
-			proj.Server.Port = 22
+	config.refs = make(map[string]map[string]reference)
+	// 인라인 server 블록은 이름순으로 servers 로 옮겨 같은 설정끼리 하나의 서버를 공유하게 함
+	names := make([]string, 0, len(config.Projects))
+	for name := range config.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		proj := config.Projects[name]
+		refs, err := resolveReferences(&proj)
+		if err != nil {
+			return nil, fmt.Errorf("프로젝트 %s: %w", name, err)
+		}
+		projectRefs, inlineServerRefs := splitServerRefs(refs)
+		if len(projectRefs) > 0 {
+			config.refs[name] = projectRefs
+		}
+
+		if proj.ServerName != "" {
+			if _, ok := config.Servers[proj.ServerName]; !ok {
+				return nil, fmt.Errorf("프로젝트 %s: 정의되지 않은 서버입니다: %s", name, proj.ServerName)
+			}
+		} else {
+			if proj.Server.Port == 0 {
+				proj.Server.Port = 22
+			}
+			config.attachServerLocked(&proj, inlineServerRefs)
+		}
+		for envName, env := range proj.Environments {
+			if env.ServerName != "" {
+				if _, ok := config.Servers[env.ServerName]; !ok {
+					return nil, fmt.Errorf("프로젝트 %s: 정의되지 않은 서버입니다: %s", QualifiedName(name, envName), env.ServerName)
+				}
+			} else if env.Server.Host != "" && env.Server.Port == 0 {
+				env.Server.Port = 22
+				proj.Environments[envName] = env
+			}
 		}
 		if proj.Branch == "" {
 			proj.Branch = "main"
@@ -83,28 +225,35 @@ func LoadConfig(path string) (*Config, error) {
 func (c *Config) GetProjects() map[string]Project {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return c.resolvedProjectsLocked()
+}
 
-	// 맵 복사본 반환 (외부 수정 방지)
+// resolvedProjectsLocked 서버 이름을 접속 설정으로 채운 프로젝트 맵 복사본 (외부 수정 방지)
+func (c *Config) resolvedProjectsLocked() map[string]Project {
 	projects := make(map[string]Project)
 	for k, v := range c.Projects {
-		projects[k] = v
+		projects[k] = c.resolveServerLocked(v)
 	}
 	return projects
 }
 
+// GetProject name 이 web@staging 형식이면 해당 환경의 설정을 상속한 프로젝트를 반환합니다
 func (c *Config) GetProject(name string) (Project, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	p, ok := c.Projects[name]
-	return p, ok
+	p, ok := c.projectLocked(name)
+	return c.resolveServerLocked(p), ok
 }
 
+// SetProject 프로젝트를 저장합니다. ServerName 없이 인라인 접속 설정만 있으면 같은 설정의 서버를 찾아 연결하거나
+// 새 서버로 등록하고, GetProject 로 받은 프로젝트의 접속 설정을 바꿨다면 서버를 수정하거나 (다른 프로젝트가 쓰면) 분리합니다.
 func (c *Config) SetProject(name string, p Project) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.Projects == nil {
 		c.Projects = make(map[string]Project)
 	}
+	c.setServerLocked(name, &p)
 	c.Projects[name] = p
 }
 
@@ -112,46 +261,22 @@ func (c *Config) DeleteProject(name string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	delete(c.Projects, name)
+	delete(c.refs, name)
 }
 
+// Deprecated: 이전 configs/ 형식은 ImportLegacy 로 sship.yaml 에 가져와 사용합니다
 func LoadServerConfig() (*ServerConfig, error) {
-	configPath := "configs/server.yaml"
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("서버 설정 파일을 읽을 수 없습니다: %v", err)
-	}
-
-	var config ServerConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("서버 설정 파일 파싱 실패: %v", err)
-	}
-
-	if config.Port == 0 {
-		config.Port = 22
-	}
-
-	return &config, nil
+	return loadServerConfigFile(filepath.Join(DefaultLegacyDir, "server.yaml"))
 }
 
+// Deprecated: 이전 configs/ 형식은 ImportLegacy 로 sship.yaml 에 가져와 사용합니다
 func LoadProjectConfig(projectName string) (*ProjectConfig, error) {
-	configPath := filepath.Join("configs/projects", projectName+".yaml")
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("프로젝트 설정 파일을 찾을 수 없습니다: %s", configPath)
-	}
-
-	var config ProjectConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("프로젝트 설정 파일 파싱 실패: %v", err)
-	}
-
-	return &config, nil
+	return loadProjectConfigFile(filepath.Join(DefaultLegacyDir, "projects", projectName+".yaml"))
 }
 
+// Deprecated: 이전 configs/ 형식은 ImportLegacy 로 sship.yaml 에 가져와 사용합니다
 func GetAvailableProjects() ([]string, error) {
-	projectsDir := "configs/projects"
+	projectsDir := filepath.Join(DefaultLegacyDir, "projects")
 
 	files, err := os.ReadDir(projectsDir)
 	if err != nil {
@@ -169,6 +294,7 @@ func GetAvailableProjects() ([]string, error) {
 	return projects, nil
 }
 
+// Deprecated: 이전 configs/ 형식은 ImportLegacy 로 sship.yaml 에 가져와 사용합니다
 func ValidateConfig(projectName string) error {
 	projectConfig, err := LoadProjectConfig(projectName)
 	if err != nil {
@@ -203,16 +329,31 @@ func ValidateConfig(projectName string) error {
 }
 
 func (c *Config) Save() error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	if c.filePath == "" {
 		return fmt.Errorf("설정 파일 경로가 지정되지 않았습니다")
 	}
 
-	data, err := yaml.Marshal(c)
-	if err != nil {
-		return fmt.Errorf("설정 직렬화 실패: %v", err)
+	// 로드 후 바뀌지 않은 값은 평문 대신 원래 참조로 저장
+	out := Config{
+		Servers:  make(map[string]ssh.ConnectionConfig, len(c.Servers)),
+		Projects: make(map[string]Project, len(c.Projects)),
+	}
+	for name, server := range c.Servers {
+		saved, err := withReferences(server, c.serverRefs[name])
+		if err != nil {
+			return fmt.Errorf("설정 직렬화 실패: %v", err)
+		}
+		out.Servers[name] = saved
+	}
+	for name, proj := range c.Projects {
+		saved, err := withReferences(proj, c.refs[name])
+		if err != nil {
+			return fmt.Errorf("설정 직렬화 실패: %v", err)
+		}
+		out.Projects[name] = saved
 	}
 
 	// 디렉토리가 없으면 생성
@@ -221,9 +362,25 @@ func (c *Config) Save() error {
 		return fmt.Errorf("설정 디렉토리 생성 실패: %v", err)
 	}
 
-	if err := os.WriteFile(c.filePath, data, 0644); err != nil {
+	// 직접 편집한 파일의 주석과 형식을 유지하며 덮어씀
+	existing, err := readExisting(c.filePath)
+	if err != nil {
+		return err
+	}
+	data, err := marshalPreserving(&out, existing)
+	if err != nil {
+		return fmt.Errorf("설정 직렬화 실패: %v", err)
+	}
+
+	// 덮어쓰기 전 내용과 새 내용을 모두 이력에 남김 (이력 저장 실패는 저장을 막지 않음)
+	c.recordFileLocked()
+	if err := writeFileAtomic(c.filePath, data, 0644); err != nil {
 		return fmt.Errorf("설정 파일 저장 실패: %v", err)
 	}
+	c.fileHash = sha256.Sum256(data)
+	if err := c.recordRevisionLocked(data); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
 
 	return nil
 }
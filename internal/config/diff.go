@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	line string
+	// 이 줄 앞까지 지나온 a, b 의 줄 수
+	aPos, bPos int
+}
+
+// unifiedDiff 두 내용의 줄 단위 차이를 unified diff 형식으로 반환합니다. 같으면 빈 문자열
+func unifiedDiff(fromName, toName string, a, b []byte) string {
+	ops := diffLines(splitLines(string(a)), splitLines(string(b)))
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// 다음 변경 위치
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// 사이의 같은 줄이 2*context 이하인 변경은 한 hunk 로 묶음
+		last := first
+		for i := first + 1; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				if i-last-1 > 2*diffContext {
+					break
+				}
+				last = i
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(ops))
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		var aCount, bCount int
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(ops[from].aPos, aCount), hunkRange(ops[from].bPos, bCount))
+		for _, op := range ops[from:to] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.line)
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange unified diff 의 "시작,줄수" (줄이 없으면 바로 앞 줄 번호)
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// LCS 표의 최대 칸 수. 바뀐 부분이 이보다 크면 줄을 맞추지 않고 통째로 바꾼 것으로 보고합니다
+const maxDiffCells = 4 << 20
+
+// diffLines 최장 공통 부분열(LCS)로 a 를 b 로 바꾸는 줄 단위 편집 목록을 만듭니다.
+// 앞뒤의 같은 줄은 표를 만들지 않고 건너뛰며, 나머지가 maxDiffCells 를 넘으면 삭제 후 추가로 처리합니다
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: ' ', line: a[i], aPos: i, bPos: i})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if int64(len(ma)+1)*int64(len(mb)+1) > maxDiffCells {
+		for i, line := range ma {
+			ops = append(ops, diffOp{kind: '-', line: line, aPos: prefix + i, bPos: prefix})
+		}
+		for j, line := range mb {
+			ops = append(ops, diffOp{kind: '+', line: line, aPos: prefix + len(ma), bPos: prefix + j})
+		}
+	} else {
+		for _, op := range lcsDiff(ma, mb) {
+			op.aPos += prefix
+			op.bPos += prefix
+			ops = append(ops, op)
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		i, j := len(a)-suffix+k, len(b)-suffix+k
+		ops = append(ops, diffOp{kind: ' ', line: a[i], aPos: i, bPos: j})
+	}
+	return ops
+}
+
+func lcsDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	// lcs[i][j] = a[i:], b[j:] 의 LCS 길이
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i], aPos: i, bPos: j})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: a[i], aPos: i, bPos: j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j], aPos: i, bPos: j})
+			j++
+		}
+	}
+	return ops
+}
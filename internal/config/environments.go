@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// 같은 앱을 여러 환경에 배포하면 프로젝트에 environments 를 두고 바뀌는 필드만 덮어씁니다
+//
+//	projects:
+//	  web:
+//	    server: vps1
+//	    path: /srv/web
+//	    environments:
+//	      staging:
+//	        branch: develop
+//	      production:
+//	        server: vps2
+//	        docker_compose: docker-compose.prod.yml
+//
+// 환경은 "web@staging" 처럼 프로젝트 이름과 함께 가리키며, GetProject 가 상속한 설정을 돌려줍니다.
+
+// EnvSeparator 프로젝트 이름과 환경 이름의 구분자
+const EnvSeparator = "@"
+
+// Environment 프로젝트 설정을 상속하고 지정한 필드만 덮어쓰는 환경. server 는 통째로 교체합니다
+type Environment struct {
+	Server        ssh.ConnectionConfig `yaml:"server,omitempty"`
+	ServerName    string               `yaml:"-"`
+	Path          string               `yaml:"path,omitempty"`
+	Branch        string               `yaml:"branch,omitempty"`
+	DockerCompose string               `yaml:"docker_compose,omitempty"`
+	HealthCheck   string               `yaml:"health_check,omitempty"`
+	EnvFile       string               `yaml:"env_file,omitempty"`
+	Port          int                  `yaml:"port,omitempty"`
+	Timeouts      StepTimeouts         `yaml:"timeouts,omitempty"`
+	Files         []FileSpec           `yaml:"files,omitempty"`
+	// Env 프로젝트의 env 에 지정한 변수만 추가하거나 덮어씁니다
+	Env map[string]string `yaml:"env,omitempty"`
+	// Hooks 지정한 단계의 훅만 교체합니다
+	Hooks Hooks `yaml:"hooks,omitempty"`
+}
+
+// UnmarshalYAML 프로젝트와 같이 server 에 서버 이름이나 접속 설정을 받습니다
+func (e *Environment) UnmarshalYAML(node *yaml.Node) error {
+	type plain Environment
+	name, err := decodeServerName(node, (*plain)(e))
+	if name != "" {
+		e.ServerName = name
+	}
+	return err
+}
+
+func (e Environment) MarshalYAML() (interface{}, error) {
+	type plain Environment
+	if e.ServerName == "" {
+		return plain(e), nil
+	}
+	e.Server = ssh.ConnectionConfig{}
+	return encodeServerName(plain(e), e.ServerName)
+}
+
+// QualifiedName 환경을 가리키는 이름 (web@staging). env 가 비어있으면 프로젝트 이름 그대로
+func QualifiedName(project, env string) string {
+	if env == "" {
+		return project
+	}
+	return project + EnvSeparator + env
+}
+
+// SplitName QualifiedName 을 프로젝트 이름과 환경 이름으로 나눕니다
+func SplitName(name string) (project, env string) {
+	project, env, _ = strings.Cut(name, EnvSeparator)
+	return project, env
+}
+
+// ValidateEnvironmentName 환경 이름은 영문, 숫자, '_', '.', '-' 로 128자 이하
+func ValidateEnvironmentName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("잘못된 환경 이름입니다 (영문, 숫자, _ . - 만 사용): %q", name)
+	}
+	return nil
+}
+
+// EnvironmentNames 환경 이름을 정렬해 반환합니다
+func (p Project) EnvironmentNames() []string {
+	names := make([]string, 0, len(p.Environments))
+	for name := range p.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withEnvironment 환경에 지정된 필드로 덮어쓴 프로젝트 설정
+func (p Project) withEnvironment(env Environment) Project {
+	switch {
+	case env.ServerName != "":
+		p.ServerName, p.Server = env.ServerName, ssh.ConnectionConfig{}
+	case env.Server.Host != "":
+		p.ServerName, p.Server = "", env.Server
+	}
+	if env.Path != "" {
+		p.Path = env.Path
+	}
+	if env.Branch != "" {
+		p.Branch = env.Branch
+	}
+	if env.DockerCompose != "" {
+		p.DockerCompose = env.DockerCompose
+	}
+	if env.HealthCheck != "" {
+		p.HealthCheck = env.HealthCheck
+	}
+	if env.EnvFile != "" {
+		p.EnvFile = env.EnvFile
+	}
+	if env.Port != 0 {
+		p.Port = env.Port
+	}
+	if env.Timeouts.Pull != 0 {
+		p.Timeouts.Pull = env.Timeouts.Pull
+	}
+	if env.Timeouts.Build != 0 {
+		p.Timeouts.Build = env.Timeouts.Build
+	}
+	if env.Timeouts.Health != 0 {
+		p.Timeouts.Health = env.Timeouts.Health
+	}
+	if env.Files != nil {
+		p.Files = env.Files
+	}
+	if len(env.Env) > 0 {
+		merged := make(map[string]string, len(p.Env)+len(env.Env))
+		for k, v := range p.Env {
+			merged[k] = v
+		}
+		for k, v := range env.Env {
+			merged[k] = v
+		}
+		p.Env = merged
+	}
+	p.Hooks = p.Hooks.withEnvironment(env.Hooks)
+	p.Environments = nil
+	return p
+}
+
+// projectLocked name 이 web@staging 형식이면 환경 설정을 상속한 프로젝트를 반환합니다
+func (c *Config) projectLocked(name string) (Project, bool) {
+	projectName, envName := SplitName(name)
+	p, ok := c.Projects[projectName]
+	if !ok || envName == "" {
+		return p, ok
+	}
+	env, ok := p.Environments[envName]
+	if !ok {
+		return Project{}, false
+	}
+	return p.withEnvironment(env), true
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvironments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `servers:
+  vps1:
+    host: 10.0.0.1
+  vps2:
+    host: 10.0.0.2
+projects:
+  web:
+    server: vps1
+    path: /srv/web
+    branch: main
+    timeouts:
+      build: 10m
+    environments:
+      staging:
+        branch: develop
+      production:
+        server: vps2
+        docker_compose: docker-compose.production.yml
+        timeouts:
+          pull: 1m
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	staging, ok := cfg.GetProject("web@staging")
+	if !ok {
+		t.Fatal("web@staging 을 찾을 수 없음")
+	}
+	if staging.Branch != "develop" || staging.Path != "/srv/web" || staging.Server.Host != "10.0.0.1" || staging.Timeouts.Build != 10*time.Minute {
+		t.Errorf("staging = %+v", staging)
+	}
+
+	production, _ := cfg.GetProject("web@production")
+	if production.Server.Host != "10.0.0.2" || production.Branch != "main" || production.DockerCompose != "docker-compose.production.yml" {
+		t.Errorf("production = %+v", production)
+	}
+	if production.Timeouts.Pull != time.Minute || production.Timeouts.Build != 10*time.Minute {
+		t.Errorf("production timeouts = %+v", production.Timeouts)
+	}
+
+	if _, ok := cfg.GetProject("web@qa"); ok {
+		t.Error("정의되지 않은 환경을 찾음")
+	}
+	if got := cfg.ProjectsUsingServer("vps2"); len(got) != 1 || got[0] != "web@production" {
+		t.Errorf("ProjectsUsingServer(vps2) = %v", got)
+	}
+	if err := cfg.DeleteServer("vps2"); !errors.Is(err, ErrServerInUse) {
+		t.Errorf("DeleteServer() error = %v, want ErrServerInUse", err)
+	}
+
+	// 저장 후 다시 읽어도 환경과 서버 참조가 유지됨
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		saved, _ := os.ReadFile(path)
+		t.Fatalf("LoadConfig() error = %v\n%s", err, saved)
+	}
+	if proj, _ := reloaded.GetProject("web@production"); proj.Server.Host != "10.0.0.2" {
+		t.Errorf("다시 읽은 production server = %+v", proj.Server)
+	}
+}
+
+func TestEnvironmentValidation(t *testing.T) {
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    environments:
+      staging:
+        server: missing
+        branch: bad..branch
+`
+	err := Validate([]byte(content))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if errs[0].Path != "projects.web.environments.staging.server" || !strings.Contains(errs[0].Message, "정의되지 않은 서버") {
+		t.Errorf("errs[0] = %v", errs[0])
+	}
+	if errs[1].Path != "projects.web.environments.staging.branch" {
+		t.Errorf("errs[1] = %v", errs[1])
+	}
+}
+
+func TestEnvironmentSecretReference(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `servers:
+  vps1:
+    host: 10.0.0.1
+  staging-vps:
+    host: 10.0.0.2
+    password: secret:staging-ssh
+projects:
+  web:
+    server: vps1
+    path: /srv/web
+    environments:
+      staging:
+        server: staging-vps
+      preview:
+        env_file: secret:preview-env
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	UseSecrets(fakeSecrets{"staging-ssh": "s3cret", "preview-env": ".env.preview"})
+	defer UseSecrets(nil)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	// 환경에서만 쓰는 시크릿도 교체 시 다시 로드하고 삭제를 막아야 함
+	if got := cfg.ProjectsUsingReference("secret:staging-ssh"); len(got) != 1 || got[0] != "web@staging" {
+		t.Errorf("ProjectsUsingReference(staging-ssh) = %v", got)
+	}
+	if got := cfg.ProjectsUsingReference("secret:preview-env"); len(got) != 1 || got[0] != "web@preview" {
+		t.Errorf("ProjectsUsingReference(preview-env) = %v", got)
+	}
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// historyDirName 설정 파일과 같은 디렉토리에 이전 버전을 보관하는 디렉토리
+	historyDirName = ".sship_history"
+	// maxRevisions 보관할 버전 수. 오래된 것부터 지웁니다
+	maxRevisions = 50
+
+	revisionTimeFormat = "20060102T150405Z"
+)
+
+var ErrRevisionNotFound = errors.New("설정 버전을 찾을 수 없습니다")
+
+// Revision 보관된 설정 파일 버전
+type Revision struct {
+	ID   int       `json:"id"`
+	Time time.Time `json:"time"`
+	Size int64     `json:"size"`
+	// Current 현재 설정 파일과 내용이 같은 버전
+	Current bool `json:"current"`
+
+	file string
+}
+
+func (c *Config) historyDirLocked() string {
+	return filepath.Join(filepath.Dir(c.filePath), historyDirName)
+}
+
+// listRevisions 버전 파일(000012-20261018T120501Z.yaml)을 ID 순으로 반환합니다
+func listRevisions(dir string) ([]Revision, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("설정 이력을 읽을 수 없습니다: %v", err)
+	}
+
+	var revisions []Revision
+	for _, entry := range entries {
+		idPart, rest, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".yaml"), "-")
+		if !ok || entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		id, err := strconv.Atoi(idPart)
+		if err != nil {
+			continue
+		}
+		t, err := time.Parse(revisionTimeFormat, rest)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, Revision{ID: id, Time: t, Size: info.Size(), file: filepath.Join(dir, entry.Name())})
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].ID < revisions[j].ID })
+	return revisions, nil
+}
+
+// recordRevisionLocked data 를 새 버전으로 보관합니다. 마지막 버전과 내용이 같으면 건너뜁니다
+func (c *Config) recordRevisionLocked(data []byte) error {
+	dir := c.historyDirLocked()
+	revisions, err := listRevisions(dir)
+	if err != nil {
+		return err
+	}
+
+	id := 1
+	if len(revisions) > 0 {
+		last := revisions[len(revisions)-1]
+		if prev, err := os.ReadFile(last.file); err == nil && bytes.Equal(prev, data) {
+			return nil
+		}
+		id = last.ID + 1
+	}
+
+	// 설정에 평문 인증 정보가 있을 수 있으므로 소유자만 읽을 수 있게 보관
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("설정 이력 디렉토리 생성 실패: %v", err)
+	}
+	name := fmt.Sprintf("%06d-%s.yaml", id, time.Now().UTC().Format(revisionTimeFormat))
+	if err := writeFileAtomic(filepath.Join(dir, name), data, 0600); err != nil {
+		return fmt.Errorf("설정 이력 저장 실패: %v", err)
+	}
+
+	for i := 0; i < len(revisions)+1-maxRevisions; i++ {
+		os.Remove(revisions[i].file)
+	}
+	return nil
+}
+
+// recordFileLocked 디스크의 현재 설정 파일을 보관합니다. 직접 편집한 내용이 덮어써지기 전에 남기기 위해 사용합니다
+func (c *Config) recordFileLocked() {
+	data, err := readExisting(c.filePath)
+	if err != nil || data == nil {
+		return
+	}
+	if err := c.recordRevisionLocked(data); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+}
+
+// Revisions 보관된 설정 버전 목록 (오래된 순)
+func (c *Config) Revisions() ([]Revision, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	revisions, err := listRevisions(c.historyDirLocked())
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if data, err := os.ReadFile(revisions[i].file); err == nil {
+			revisions[i].Current = sha256.Sum256(data) == c.fileHash
+		}
+	}
+	if revisions == nil {
+		revisions = []Revision{}
+	}
+	return revisions, nil
+}
+
+// RevisionContent 버전의 파일 내용. id 가 0 이면 현재 설정 파일
+func (c *Config) RevisionContent(id int) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.revisionContentLocked(id)
+}
+
+func (c *Config) revisionContentLocked(id int) ([]byte, error) {
+	if id == 0 {
+		return os.ReadFile(c.filePath)
+	}
+	revisions, err := listRevisions(c.historyDirLocked())
+	if err != nil {
+		return nil, err
+	}
+	for _, rev := range revisions {
+		if rev.ID == id {
+			return os.ReadFile(rev.file)
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrRevisionNotFound, id)
+}
+
+// Diff 두 버전의 차이를 unified diff 형식으로 반환합니다. 0 은 현재 설정 파일
+func (c *Config) Diff(from, to int) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	a, err := c.revisionContentLocked(from)
+	if err != nil {
+		return "", err
+	}
+	b, err := c.revisionContentLocked(to)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(revisionLabel(from), revisionLabel(to), a, b), nil
+}
+
+func revisionLabel(id int) string {
+	if id == 0 {
+		return "current"
+	}
+	return "revision " + strconv.Itoa(id)
+}
+
+// Restore 보관된 버전으로 설정 파일을 되돌리고 다시 로드합니다. 되돌린 내용도 새 버전으로 기록됩니다
+func (c *Config) Restore(id int) (Change, error) {
+	data, err := c.RevisionContent(id)
+	if err != nil {
+		return Change{}, err
+	}
+	if err := Validate(data); err != nil {
+		return Change{}, fmt.Errorf("설정 검증 실패:\n%w", err)
+	}
+	loaded, err := parseConfig(data)
+	if err != nil {
+		return Change{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.recordFileLocked()
+	if err := writeFileAtomic(c.filePath, data, 0644); err != nil {
+		return Change{}, fmt.Errorf("설정 파일 저장 실패: %v", err)
+	}
+	c.fileHash = sha256.Sum256(data)
+	if err := c.recordRevisionLocked(data); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+	return c.replaceLocked(loaded), nil
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSavePreservesComments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sship.yaml")
+	content := `# 운영 서버 설정
+servers:
+  vps1:
+    host: 10.0.0.1 # 메인 VPS
+    user: deploy
+projects:
+  # 웹 프론트엔드
+  web:
+    server: vps1
+    path: /srv/web
+    branch: "main"
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	proj, _ := cfg.GetProject("web")
+	proj.Branch = "release"
+	cfg.SetProject("web", proj)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# 운영 서버 설정\nservers:\n  vps1:\n",
+		"host: 10.0.0.1 # 메인 VPS",
+		"  # 웹 프론트엔드\n  web:\n",
+		`branch: "release"`,
+	} {
+		if !strings.Contains(string(saved), want) {
+			t.Errorf("저장된 설정에 %q 없음:\n%s", want, saved)
+		}
+	}
+	for _, unwanted := range []string{"health_check", "env_file", `password: ""`} {
+		if strings.Contains(string(saved), unwanted) {
+			t.Errorf("빈 값 %q 이 추가됨:\n%s", unwanted, saved)
+		}
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("파일 권한 = %v, want 0600", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".sship.yaml-") {
+			t.Errorf("임시 파일이 남음: %s", entry.Name())
+		}
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    branch: main
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	for _, branch := range []string{"develop", "release"} {
+		proj, _ := cfg.GetProject("web")
+		proj.Branch = branch
+		cfg.SetProject("web", proj)
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// 직접 작성한 원본 + 저장 두 번
+	revisions, err := cfg.Revisions()
+	if err != nil {
+		t.Fatalf("Revisions() error = %v", err)
+	}
+	if len(revisions) != 3 || !revisions[2].Current || revisions[0].Current {
+		t.Fatalf("Revisions() = %+v", revisions)
+	}
+	if original, _ := cfg.RevisionContent(1); string(original) != content {
+		t.Errorf("첫 버전이 원본과 다름:\n%s", original)
+	}
+
+	diff, err := cfg.Diff(2, 3)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !strings.Contains(diff, "-    branch: develop\n+    branch: release\n") || !strings.HasPrefix(diff, "--- revision 2\n+++ revision 3\n@@ ") {
+		t.Errorf("Diff() =\n%s", diff)
+	}
+	if diff, _ := cfg.Diff(3, 0); diff != "" {
+		t.Errorf("현재 파일과 마지막 버전의 차이 =\n%s", diff)
+	}
+
+	change, err := cfg.Restore(1)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(change.Updated) != 1 {
+		t.Errorf("Restore() change = %+v", change)
+	}
+	if proj, _ := cfg.GetProject("web"); proj.Branch != "main" {
+		t.Errorf("되돌린 branch = %q", proj.Branch)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("되돌린 파일이 원본과 다름:\n%s", data)
+	}
+	if revisions, _ := cfg.Revisions(); len(revisions) != 4 || !revisions[3].Current {
+		t.Errorf("되돌린 내용이 새 버전으로 기록되지 않음: %+v", revisions)
+	}
+
+	if _, err := cfg.Restore(99); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("Restore(99) error = %v, want ErrRevisionNotFound", err)
+	}
+}
+
+func TestRestoreUnresolvedReference(t *testing.T) {
+	t.Setenv("SSHIP_TEST_DEPLOY_PASSWORD", "hunter2")
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+      password: ${SSHIP_TEST_DEPLOY_PASSWORD}
+    path: /srv/web
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	proj, _ := cfg.GetProject("web")
+	proj.Branch = "develop"
+	cfg.SetProject("web", proj)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// 버전을 만든 뒤 참조하던 환경변수가 사라진 경우
+	os.Unsetenv("SSHIP_TEST_DEPLOY_PASSWORD")
+	var refErr *ReferenceError
+	if _, err := cfg.Restore(1); !errors.As(err, &refErr) || !strings.Contains(err.Error(), "SSHIP_TEST_DEPLOY_PASSWORD") {
+		t.Errorf("Restore() error = %v, want ReferenceError", err)
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	// 바뀐 부분이 커도 LCS 표를 만들지 않고 삭제 후 추가로 보고
+	a := make([]string, 5000)
+	b := make([]string, 5000)
+	for i := range a {
+		a[i] = fmt.Sprintf("a%d", i)
+		b[i] = fmt.Sprintf("b%d", i)
+	}
+	a[0], b[0] = "same", "same"
+
+	diff := unifiedDiff("a", "b", []byte(strings.Join(a, "\n")+"\n"), []byte(strings.Join(b, "\n")+"\n"))
+	if !strings.HasPrefix(diff, "--- a\n+++ b\n@@ -1,5000 +1,5000 @@\n same\n-a1\n") || !strings.HasSuffix(diff, "+b4999\n") {
+		t.Errorf("Diff() =\n%.200s", diff)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 배포 단계 전후에 프로젝트 경로에서 원격 명령을 실행합니다
+//
+//	projects:
+//	  web:
+//	    hooks:
+//	      pre_up:
+//	        - docker compose run --rm web ./migrate
+//	      post_up:
+//	        - run: curl -fsS http://127.0.0.1:8080/warmup
+//	          continue_on_error: true
+//	          timeout: 2m
+//	      on_failure:
+//	        - docker compose logs --tail=100
+//
+// 명령이 실패하면 배포를 중단하고 on_failure 를 실행합니다. continue_on_error 면 실패를 기록만 하고 계속합니다.
+
+// Hook 단계 전후에 실행할 명령. 문자열만 쓰면 Run 으로 읽습니다
+type Hook struct {
+	Run             string        `yaml:"run" json:"run"`
+	ContinueOnError bool          `yaml:"continue_on_error,omitempty" json:"continue_on_error,omitempty"`
+	Timeout         time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Hooks 배포 단계별 훅. 목록 순서대로 실행합니다
+type Hooks struct {
+	PrePull   []Hook `yaml:"pre_pull,omitempty" json:"pre_pull,omitempty"`
+	PostPull  []Hook `yaml:"post_pull,omitempty" json:"post_pull,omitempty"`
+	PreUp     []Hook `yaml:"pre_up,omitempty" json:"pre_up,omitempty"`
+	PostUp    []Hook `yaml:"post_up,omitempty" json:"post_up,omitempty"`
+	OnFailure []Hook `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
+}
+
+func (h *Hook) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*h = Hook{Run: node.Value}
+		return nil
+	}
+	type plain Hook
+	return node.Decode((*plain)(h))
+}
+
+func (h Hook) MarshalYAML() (interface{}, error) {
+	if !h.ContinueOnError && h.Timeout == 0 {
+		return h.Run, nil
+	}
+	type plain Hook
+	return plain(h), nil
+}
+
+// IsZero 환경에서 단계를 비우는 빈 목록(pre_up: [])은 설정된 것으로 봅니다
+func (h Hooks) IsZero() bool {
+	return h.PrePull == nil && h.PostPull == nil && h.PreUp == nil && h.PostUp == nil && h.OnFailure == nil
+}
+
+// MarshalYAML 빈 목록도 omitempty 로 빠지지 않도록 저장합니다
+func (h Hooks) MarshalYAML() (interface{}, error) {
+	stage := func(hooks []Hook) *[]Hook {
+		if hooks == nil {
+			return nil
+		}
+		return &hooks
+	}
+	return struct {
+		PrePull   *[]Hook `yaml:"pre_pull,omitempty"`
+		PostPull  *[]Hook `yaml:"post_pull,omitempty"`
+		PreUp     *[]Hook `yaml:"pre_up,omitempty"`
+		PostUp    *[]Hook `yaml:"post_up,omitempty"`
+		OnFailure *[]Hook `yaml:"on_failure,omitempty"`
+	}{stage(h.PrePull), stage(h.PostPull), stage(h.PreUp), stage(h.PostUp), stage(h.OnFailure)}, nil
+}
+
+// withEnvironment 환경에 지정된 단계의 훅으로 교체합니다
+func (h Hooks) withEnvironment(env Hooks) Hooks {
+	if env.PrePull != nil {
+		h.PrePull = env.PrePull
+	}
+	if env.PostPull != nil {
+		h.PostPull = env.PostPull
+	}
+	if env.PreUp != nil {
+		h.PreUp = env.PreUp
+	}
+	if env.PostUp != nil {
+		h.PostUp = env.PostUp
+	}
+	if env.OnFailure != nil {
+		h.OnFailure = env.OnFailure
+	}
+	return h
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://sship-host")
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    hooks:
+      pre_up:
+        - ./migrate --url "${DATABASE_URL}"
+      post_up:
+        - run: curl -fsS localhost/warmup
+          continue_on_error: true
+          timeout: 2m
+    environments:
+      staging:
+        hooks:
+          pre_up: []
+          on_failure:
+            - docker compose logs --tail=100
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	web, _ := cfg.GetProject("web")
+	if len(web.Hooks.PreUp) != 1 || web.Hooks.PreUp[0] != (Hook{Run: `./migrate --url "${DATABASE_URL}"`}) {
+		t.Errorf("pre_up = %+v", web.Hooks.PreUp)
+	}
+	if want := (Hook{Run: "curl -fsS localhost/warmup", ContinueOnError: true, Timeout: 2 * time.Minute}); len(web.Hooks.PostUp) != 1 || web.Hooks.PostUp[0] != want {
+		t.Errorf("post_up = %+v", web.Hooks.PostUp)
+	}
+
+	// 환경은 지정한 단계만 교체
+	staging, _ := cfg.GetProject("web@staging")
+	if len(staging.Hooks.PreUp) != 0 || len(staging.Hooks.PostUp) != 1 || len(staging.Hooks.OnFailure) != 1 {
+		t.Errorf("staging hooks = %+v", staging.Hooks)
+	}
+
+	// 훅 명령의 ${VAR} 는 서버 셸이 해석하도록 그대로 두고, 문자열 형식과 단계를 비우는 빈 목록도 그대로 저장
+	web.Branch = "main"
+	cfg.SetProject("web", web)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "pre_up:\n        - ./migrate --url \"${DATABASE_URL}\"\n") || !strings.Contains(string(data), "pre_up: []") {
+		t.Errorf("저장된 훅:\n%s", data)
+	}
+}
+
+func TestHookValidation(t *testing.T) {
+	err := Validate([]byte(`projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    hooks:
+      pre_pul:
+        - echo
+      pre_up:
+        - ""
+        - run: "  "
+`))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "pre_pull") {
+		t.Errorf("Validate() = %v", errs)
+	}
+
+	err = Validate([]byte(`projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    hooks:
+      pre_up:
+        - ""
+        - run: "  "
+`))
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Path != "projects.web.hooks.pre_up[0]" || errs[1].Path != "projects.web.hooks.pre_up[1]" {
+		t.Errorf("Validate() = %v", err)
+	}
+}
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLegacyDir 이전 버전이 사용하던 설정 디렉토리 (server.yaml, projects/*.yaml)
+const DefaultLegacyDir = "configs"
+
+// ImportAction 가져오기 결과 종류
+type ImportAction string
+
+const (
+	ImportAdd       ImportAction = "add"
+	ImportUpdate    ImportAction = "update"
+	ImportUnchanged ImportAction = "unchanged"
+	// ImportSkip 이미 있는 프로젝트와 다르지만 Overwrite 를 지정하지 않은 경우
+	ImportSkip    ImportAction = "skip"
+	ImportInvalid ImportAction = "invalid"
+)
+
+// ImportOptions DryRun 이면 sship.yaml 을 바꾸지 않고 결과만 보고합니다.
+// Overwrite 면 이미 있는 프로젝트에 이전 설정의 값을 덮어씁니다
+type ImportOptions struct {
+	DryRun    bool `json:"dry_run"`
+	Overwrite bool `json:"overwrite"`
+}
+
+// FieldChange 바뀌는 필드 (server.host 형식). 비밀번호 등은 가려서 보고합니다
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+type ImportResult struct {
+	File    string        `json:"file"`
+	Project string        `json:"project,omitempty"`
+	Action  ImportAction  `json:"action"`
+	Changes []FieldChange `json:"changes,omitempty"`
+	// Warnings 가져오면 평문 비밀번호가 저장되는 경우 등 확인이 필요한 내용
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Dir     string         `json:"dir"`
+	DryRun  bool           `json:"dry_run"`
+	Results []ImportResult `json:"results"`
+}
+
+func (r ImportReport) String() string {
+	var b strings.Builder
+	prefix := ""
+	if r.DryRun {
+		prefix = "[dry-run] "
+	}
+	fmt.Fprintf(&b, "%s%s 에서 프로젝트 파일 %d개 확인\n", prefix, filepath.Join(r.Dir, "projects"), len(r.Results))
+	for _, res := range r.Results {
+		switch res.Action {
+		case ImportAdd:
+			fmt.Fprintf(&b, "+ %s (%s) 추가\n", res.Project, res.File)
+		case ImportUpdate:
+			fmt.Fprintf(&b, "~ %s (%s) 변경\n", res.Project, res.File)
+		case ImportSkip:
+			fmt.Fprintf(&b, "- %s (%s) 이미 있는 프로젝트라 건너뜀 (-overwrite 로 덮어쓰기)\n", res.Project, res.File)
+		case ImportUnchanged:
+			fmt.Fprintf(&b, "= %s (%s) 변경 없음\n", res.Project, res.File)
+		case ImportInvalid:
+			fmt.Fprintf(&b, "! %s: %s\n", res.File, strings.ReplaceAll(res.Error, "\n", "\n    "))
+		}
+		for _, ch := range res.Changes {
+			switch {
+			case ch.Old == "":
+				fmt.Fprintf(&b, "    %s: %s\n", ch.Field, ch.New)
+			case ch.New == "":
+				fmt.Fprintf(&b, "    %s: %s → (없음)\n", ch.Field, ch.Old)
+			default:
+				fmt.Fprintf(&b, "    %s: %s → %s\n", ch.Field, ch.Old, ch.New)
+			}
+		}
+		for _, warning := range res.Warnings {
+			fmt.Fprintf(&b, "    ⚠️ %s\n", warning)
+		}
+	}
+	return b.String()
+}
+
+// Change 가져오기로 추가/변경된 프로젝트. DryRun 이면 비어 있습니다
+func (r ImportReport) Change() Change {
+	var change Change
+	if r.DryRun {
+		return change
+	}
+	for _, res := range r.Results {
+		switch res.Action {
+		case ImportAdd:
+			change.Added = append(change.Added, res.Project)
+		case ImportUpdate:
+			change.Updated = append(change.Updated, res.Project)
+		}
+	}
+	return change
+}
+
+// LegacyDir 설정 파일과 같은 디렉토리의 configs. API 의 가져오기는 이 디렉토리만 읽습니다
+func (c *Config) LegacyDir() string {
+	if c.filePath == "" {
+		return DefaultLegacyDir
+	}
+	return filepath.Join(filepath.Dir(c.filePath), DefaultLegacyDir)
+}
+
+// ImportLegacy dir/projects/*.yaml 의 이전 프로젝트 설정을 현재 형식으로 변환해 가져옵니다.
+// 서버 정보가 비어 있는 필드는 dir/server.yaml 의 값을 사용하며, DryRun 이 아니면 바뀐 내용을 저장합니다
+func (c *Config) ImportLegacy(dir string, opts ImportOptions) (ImportReport, error) {
+	report := ImportReport{Dir: dir, DryRun: opts.DryRun, Results: []ImportResult{}}
+
+	projectsDir := filepath.Join(dir, "projects")
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return report, fmt.Errorf("프로젝트 디렉토리를 읽을 수 없습니다: %w", err)
+	}
+
+	var defaults *ServerConfig
+	if defaults, err = loadServerConfigFile(filepath.Join(dir, "server.yaml")); errors.Is(err, os.ErrNotExist) {
+		defaults = nil
+	} else if err != nil {
+		return report, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".yaml" {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+
+	imported := make(map[string]Project)
+	seen := make(map[string]string)
+	for _, file := range files {
+		res := ImportResult{File: file}
+		name, proj, err := convertLegacy(filepath.Join(projectsDir, file), defaults)
+		if err == nil && seen[name] != "" {
+			err = fmt.Errorf("%s 와 같은 프로젝트 이름입니다: %s", seen[name], name)
+		}
+		if err != nil {
+			res.Action, res.Error = ImportInvalid, err.Error()
+			report.Results = append(report.Results, res)
+			continue
+		}
+		seen[name] = file
+		res.Project = name
+
+		existing, exists := c.GetProject(name)
+		if exists {
+			proj = existing.withLegacy(proj)
+		}
+		res.Changes = diffFields(existing, proj, exists)
+
+		switch {
+		case !exists:
+			res.Action = ImportAdd
+		case len(res.Changes) == 0:
+			res.Action = ImportUnchanged
+		case !opts.Overwrite:
+			res.Action = ImportSkip
+		default:
+			res.Action = ImportUpdate
+		}
+		if res.Action == ImportAdd || res.Action == ImportUpdate {
+			imported[name] = proj
+			res.Warnings = plaintextWarnings(res.Changes)
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	if opts.DryRun || len(imported) == 0 {
+		return report, nil
+	}
+	// 저장에 실패하면 메모리의 설정도 파일과 같게 되돌림
+	prev := c.snapshot()
+	for name, proj := range imported {
+		c.SetProject(name, proj)
+	}
+	if err := c.Save(); err != nil {
+		c.restore(prev)
+		return report, err
+	}
+	return report, nil
+}
+
+// snapshot 서버와 프로젝트 목록의 복사본. SetProject 는 맵의 항목만 바꾸므로 얕은 복사로 충분합니다
+func (c *Config) snapshot() *Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &Config{
+		Servers:    maps.Clone(c.Servers),
+		Projects:   maps.Clone(c.Projects),
+		refs:       maps.Clone(c.refs),
+		serverRefs: maps.Clone(c.serverRefs),
+	}
+}
+
+func (c *Config) restore(prev *Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replaceLocked(prev)
+}
+
+// convertLegacy 이전 프로젝트 파일 하나를 현재 형식으로 변환하고 검증합니다. 이름이 없으면 파일 이름을 사용합니다
+func convertLegacy(path string, defaults *ServerConfig) (string, Project, error) {
+	legacy, err := loadProjectConfigFile(path)
+	if err != nil {
+		return "", Project{}, err
+	}
+	lp := legacy.Project
+
+	name := lp.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), ".yaml")
+	}
+
+	server := lp.Server
+	if defaults != nil {
+		if server.Host == "" {
+			server.Host = defaults.Host
+		}
+		if server.Port == 0 {
+			server.Port = defaults.Port
+		}
+		if server.User == "" {
+			server.User = defaults.User
+		}
+		if server.Password == "" {
+			server.Password = defaults.Password
+		}
+	}
+	if server.Port == 0 {
+		server.Port = 22
+	}
+
+	proj := Project{
+		Server: ssh.ConnectionConfig{
+			Host:     server.Host,
+			Port:     server.Port,
+			User:     server.User,
+			Password: server.Password,
+		},
+		Path:          lp.Path,
+		Branch:        lp.Branch,
+		DockerCompose: lp.ComposeFile,
+		Repository:    lp.Repository,
+		Domain:        lp.Domain,
+		Type:          lp.Type,
+	}
+	if proj.Branch == "" {
+		proj.Branch = "main"
+	}
+	if proj.DockerCompose == "" {
+		proj.DockerCompose = "docker-compose.prod.yml"
+	}
+
+	// sship.yaml 에 넣었을 때와 같은 규칙으로 검증
+	data, err := yaml.Marshal(Config{Projects: map[string]Project{name: proj}})
+	if err != nil {
+		return "", Project{}, err
+	}
+	if err := Validate(data); err != nil {
+		return "", Project{}, err
+	}
+	return name, proj, nil
+}
+
+// plaintextWarnings 이전 설정의 비밀번호는 평문이라 그대로 가져오면 sship.yaml 과 설정 이력에 남습니다
+func plaintextWarnings(changes []FieldChange) []string {
+	var warnings []string
+	for _, ch := range changes {
+		if ch.New != "" && isSecretField(ch.Field) {
+			warnings = append(warnings, fmt.Sprintf("%s 가 sship.yaml 과 설정 이력에 평문으로 저장됩니다. 가져온 뒤 secret: 참조로 바꾸세요", ch.Field))
+		}
+	}
+	return warnings
+}
+
+// withLegacy 이미 있는 프로젝트에 이전 설정이 가진 값만 덮어씁니다 (키 파일, 환경 등은 유지)
+func (p Project) withLegacy(legacy Project) Project {
+	p.Server.Host = legacy.Server.Host
+	p.Server.Port = legacy.Server.Port
+	if legacy.Server.User != "" {
+		p.Server.User = legacy.Server.User
+	}
+	if legacy.Server.Password != "" {
+		p.Server.Password = legacy.Server.Password
+	}
+	p.Path = legacy.Path
+	p.Branch = legacy.Branch
+	p.DockerCompose = legacy.DockerCompose
+	if legacy.Repository != "" {
+		p.Repository = legacy.Repository
+	}
+	if legacy.Domain != "" {
+		p.Domain = legacy.Domain
+	}
+	if legacy.Type != "" {
+		p.Type = legacy.Type
+	}
+	return p
+}
+
+// diffFields 두 프로젝트 설정의 값이 다른 필드. exists 가 false 면 new 의 모든 필드를 추가로 보고합니다
+func diffFields(old, new Project, exists bool) []FieldChange {
+	oldFields := map[string]string{}
+	if exists {
+		oldFields = flattenProject(old)
+	}
+	newFields := flattenProject(new)
+
+	var changes []FieldChange
+	for field, value := range newFields {
+		if oldFields[field] != value {
+			changes = append(changes, FieldChange{Field: field, Old: maskField(field, oldFields[field]), New: maskField(field, value)})
+		}
+	}
+	for field, value := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Old: maskField(field, value)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// flattenProject 접속 설정을 포함한 프로젝트의 값을 server.host 형식의 경로별로 펼칩니다 (빈 값 제외)
+func flattenProject(p Project) map[string]string {
+	p.ServerName = ""
+	var node yaml.Node
+	if err := node.Encode(p); err != nil {
+		return nil
+	}
+	fields := make(map[string]string)
+	var walk func(n *yaml.Node, path string)
+	walk = func(n *yaml.Node, path string) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				walk(n.Content[i+1], joinPath(path, n.Content[i].Value))
+			}
+		case yaml.SequenceNode:
+			for i, item := range n.Content {
+				walk(item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		case yaml.ScalarNode:
+			if !isZeroNode(n) {
+				fields[path] = n.Value
+			}
+		}
+	}
+	walk(&node, "")
+	return fields
+}
+
+func maskField(field, value string) string {
+	if value != "" && isSecretField(field) {
+		return "****"
+	}
+	return value
+}
+
+func isSecretField(field string) bool {
+	last := field[strings.LastIndex(field, ".")+1:]
+	return strings.Contains(last, "password") || strings.Contains(last, "passphrase") || last == "private_key"
+}
+
+func loadServerConfigFile(path string) (*ServerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("서버 설정 파일을 읽을 수 없습니다: %w", err)
+	}
+
+	var config ServerConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("서버 설정 파일 파싱 실패: %v", err)
+	}
+	if config.Port == 0 {
+		config.Port = 22
+	}
+	return &config, nil
+}
+
+func loadProjectConfigFile(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("프로젝트 설정 파일을 찾을 수 없습니다: %s", path)
+	}
+
+	var config ProjectConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("프로젝트 설정 파일 파싱 실패: %v", err)
+	}
+	return &config, nil
+}
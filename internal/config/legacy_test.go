@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportLegacy(t *testing.T) {
+	dir := t.TempDir()
+	legacyDir := filepath.Join(dir, "configs")
+	if err := os.MkdirAll(filepath.Join(legacyDir, "projects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"server.yaml": `host: 10.0.0.1
+user: deploy
+password: hunter2
+`,
+		"projects/web.yaml": `project:
+  name: web
+  type: node
+  repository: git@github.com:example/web.git
+  domain: web.example.com
+  path: /srv/web
+  branch: main
+  compose_file: docker-compose.yml
+`,
+		"projects/blog.yaml": `project:
+  name: blog
+  path: /srv/blog
+  branch: develop
+`,
+		"projects/broken.yaml": `project:
+  name: broken
+  path: relative/path
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(legacyDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, "sship.yaml")
+	content := `projects:
+  blog:
+    server:
+      host: 10.0.0.1
+      user: deploy
+      key_file: ~/.ssh/blog
+    path: /srv/blog
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	report, err := cfg.ImportLegacy(legacyDir, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportLegacy() error = %v", err)
+	}
+	actions := map[string]ImportAction{}
+	for _, res := range report.Results {
+		actions[res.File] = res.Action
+	}
+	if actions["web.yaml"] != ImportAdd || actions["blog.yaml"] != ImportSkip || actions["broken.yaml"] != ImportInvalid {
+		t.Errorf("dry-run 결과 = %+v", report.Results)
+	}
+	if text := report.String(); !strings.Contains(text, "server.password: ****") || strings.Contains(text, "hunter2") {
+		t.Errorf("비밀번호가 보고서에 노출됨:\n%s", text)
+	}
+	if !strings.Contains(report.String(), "server.password 가 sship.yaml 과 설정 이력에 평문으로 저장됩니다") {
+		t.Errorf("평문 비밀번호 경고가 없음:\n%s", report.String())
+	}
+	if !strings.Contains(report.String(), "branch: main → develop") {
+		t.Errorf("바뀔 필드가 보고되지 않음:\n%s", report.String())
+	}
+	if _, ok := cfg.GetProject("web"); ok {
+		t.Error("dry-run 에서 프로젝트가 추가됨")
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("dry-run 에서 설정 파일이 바뀜:\n%s", data)
+	}
+
+	if _, err := cfg.ImportLegacy(legacyDir, ImportOptions{Overwrite: true}); err != nil {
+		t.Fatalf("ImportLegacy() error = %v", err)
+	}
+	web, ok := cfg.GetProject("web")
+	if !ok || web.Server.Host != "10.0.0.1" || web.Server.Password != "hunter2" || web.Repository != "git@github.com:example/web.git" || web.Domain != "web.example.com" || web.Type != "node" {
+		t.Errorf("가져온 web = %+v", web)
+	}
+	blog, _ := cfg.GetProject("blog")
+	if blog.Branch != "develop" || blog.Server.KeyFile != "~/.ssh/blog" {
+		t.Errorf("덮어쓴 blog = %+v", blog)
+	}
+
+	// 저장된 파일을 다시 읽어도 같은 결과
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if proj, _ := reloaded.GetProject("web"); proj.Repository != web.Repository || proj.DockerCompose != "docker-compose.yml" {
+		t.Errorf("다시 읽은 web = %+v", proj)
+	}
+	report, _ = reloaded.ImportLegacy(legacyDir, ImportOptions{DryRun: true})
+	for _, res := range report.Results {
+		if res.File != "broken.yaml" && res.Action != ImportUnchanged {
+			t.Errorf("다시 가져오기 결과 %s = %s %+v", res.File, res.Action, res.Changes)
+		}
+	}
+}
+
+func TestImportLegacySaveFailure(t *testing.T) {
+	dir := t.TempDir()
+	legacyDir := filepath.Join(dir, "configs")
+	if err := os.MkdirAll(filepath.Join(legacyDir, "projects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `project:
+  name: web
+  path: /srv/web
+  server:
+    host: 10.0.0.1
+`
+	if err := os.WriteFile(filepath.Join(legacyDir, "projects", "web.yaml"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 상위 경로가 일반 파일이라 저장이 실패함
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{Projects: map[string]Project{}}
+	cfg.SetFilePath(filepath.Join(blocker, "sship.yaml"))
+
+	if _, err := cfg.ImportLegacy(legacyDir, ImportOptions{}); err == nil {
+		t.Fatal("ImportLegacy() 저장 실패가 반환되지 않음")
+	}
+	if _, ok := cfg.GetProject("web"); ok {
+		t.Error("저장에 실패했는데 프로젝트가 메모리에 남음")
+	}
+	if len(cfg.GetServers()) != 0 {
+		t.Errorf("저장에 실패했는데 서버가 등록됨: %v", cfg.GetServers())
+	}
+}
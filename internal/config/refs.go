@@ -0,0 +1,301 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 문자열 값에 쓸 수 있는 참조
+//
+//	password: ${DEPLOY_PASSWORD}          환경변수
+//	host: ${DEPLOY_HOST:-10.0.0.1}        환경변수 (없으면 기본값)
+//	private_key: file:~/.ssh/deploy_key   파일 내용 (끝의 개행 제외)
+//	password: secret:db-password          sship 시크릿 저장소의 값
+const (
+	filePrefix   = "file:"
+	secretPrefix = "secret:"
+)
+
+// SecretResolver secret:NAME 참조를 해석합니다. *secrets.Store 가 구현합니다
+type SecretResolver interface {
+	Get(name string) (string, bool)
+}
+
+var (
+	secretsMu      sync.RWMutex
+	secretResolver SecretResolver
+)
+
+// UseSecrets secret:NAME 참조에 사용할 시크릿 저장소를 지정합니다
+func UseSecrets(resolver SecretResolver) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secretResolver = resolver
+}
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// reference 로드 시 해석한 참조. Save 할 때 값이 그대로면 원래 참조로 되돌려 씁니다
+type reference struct {
+	raw      string
+	resolved string
+}
+
+func isReference(value string) bool {
+	return strings.HasPrefix(value, filePrefix) || strings.HasPrefix(value, secretPrefix) || envRefPattern.MatchString(value)
+}
+
+// resolveReference 참조를 실제 값으로 바꿉니다
+func resolveReference(raw string) (string, error) {
+	if strings.HasPrefix(raw, secretPrefix) {
+		name := strings.TrimPrefix(raw, secretPrefix)
+		secretsMu.RLock()
+		resolver := secretResolver
+		secretsMu.RUnlock()
+		if resolver == nil {
+			return "", fmt.Errorf("시크릿 저장소가 설정되지 않았습니다 (secret:%s)", name)
+		}
+		value, ok := resolver.Get(name)
+		if !ok {
+			return "", fmt.Errorf("시크릿을 찾을 수 없습니다: %s", name)
+		}
+		return value, nil
+	}
+
+	if strings.HasPrefix(raw, filePrefix) {
+		path := strings.TrimPrefix(raw, filePrefix)
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[1:])
+			}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("참조 파일을 읽을 수 없습니다: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	var missing []string
+	resolved := envRefPattern.ReplaceAllStringFunc(raw, func(ref string) string {
+		m := envRefPattern.FindStringSubmatch(ref)
+		if value, ok := os.LookupEnv(m[1]); ok {
+			return value
+		}
+		if strings.Contains(ref, ":-") {
+			return m[2]
+		}
+		missing = append(missing, m[1])
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("환경변수가 설정되지 않았습니다: %s", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// ReferenceError 해석하지 못한 참조 (설정되지 않은 환경변수, 없는 시크릿이나 파일 등)
+type ReferenceError struct {
+	Path string
+	Err  error
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ReferenceError) Unwrap() error {
+	return e.Err
+}
+
+// resolveReferences 프로젝트나 서버(포인터)의 모든 문자열 필드에서 참조를 해석하고, 필드 경로별 원래 값을 반환합니다
+func resolveReferences(v interface{}) (map[string]reference, error) {
+	refs := make(map[string]reference)
+	err := visitStrings(reflect.ValueOf(v).Elem(), "", func(path string, field reflect.Value) error {
+		raw := field.String()
+		if !isReference(raw) {
+			return nil
+		}
+		resolved, err := resolveReference(raw)
+		if err != nil {
+			return &ReferenceError{Path: path, Err: err}
+		}
+		field.SetString(resolved)
+		refs[path] = reference{raw: raw, resolved: resolved}
+		return nil
+	})
+	return refs, err
+}
+
+// withReferences 저장용 복사본. 로드 후 값이 바뀌지 않은 필드는 원래 참조로 되돌립니다
+func withReferences[T any](v T, refs map[string]reference) (T, error) {
+	if len(refs) == 0 {
+		return v, nil
+	}
+
+	// 슬라이스 등을 원본과 공유하지 않도록 깊은 복사
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return v, err
+	}
+	var out T
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return v, err
+	}
+
+	err = visitStrings(reflect.ValueOf(&out).Elem(), "", func(path string, field reflect.Value) error {
+		if ref, ok := refs[path]; ok && field.String() == ref.resolved {
+			field.SetString(ref.raw)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// 훅 명령은 서버의 셸이 해석하므로 ${VAR} 등을 참조로 보지 않고 그대로 둡니다
+var hooksType = reflect.TypeOf(Hooks{})
+
+// visitStrings v 안의 모든 문자열 값을 yaml 경로 (server.jump[0].password 형식) 와 함께 방문합니다
+func visitStrings(v reflect.Value, path string, fn func(path string, field reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.String:
+		return fn(path, v)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return visitStrings(v.Elem(), path, fn)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, inline, ok := yamlName(t.Field(i))
+			if !ok || t.Field(i).Type == hooksType {
+				continue
+			}
+			fieldPath := joinPath(path, name)
+			if inline {
+				fieldPath = path
+			}
+			if err := visitStrings(v.Field(i), fieldPath, fn); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := visitStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		// 맵 값은 주소를 얻을 수 없으므로 복사본을 방문한 뒤 다시 넣음
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := visitStrings(elem, joinPath(path, fmt.Sprint(key.Interface())), fn); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+	}
+	return nil
+}
+
+// ProjectsUsingReference raw 참조(예: secret:db-password)를 직접 또는 서버 설정으로 사용하는 프로젝트 이름.
+// 환경의 필드나 환경이 지정한 서버에서 사용하면 web@staging 형식의 이름을 반환합니다
+func (c *Config) ProjectsUsingReference(raw string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var names []string
+	for name, proj := range c.Projects {
+		envPrefix := "environments."
+		used := proj.ServerName != "" && hasReference(c.serverRefs[proj.ServerName], raw)
+		for path, ref := range c.refs[name] {
+			if ref.raw == raw && !strings.HasPrefix(path, envPrefix) {
+				used = true
+			}
+		}
+		if used {
+			names = append(names, name)
+		}
+
+		for envName, env := range proj.Environments {
+			prefix := envPrefix + envName + "."
+			used := env.ServerName != "" && hasReference(c.serverRefs[env.ServerName], raw)
+			for path, ref := range c.refs[name] {
+				if ref.raw == raw && strings.HasPrefix(path, prefix) {
+					used = true
+				}
+			}
+			if used {
+				names = append(names, QualifiedName(name, envName))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServersUsingReference raw 참조를 사용하는 servers: 항목 이름. 아직 어떤 프로젝트도 참조하지 않는 서버도 포함합니다
+func (c *Config) ServersUsingReference(raw string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var names []string
+	for name, refs := range c.serverRefs {
+		if hasReference(refs, raw) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func hasReference(refs map[string]reference, raw string) bool {
+	for _, ref := range refs {
+		if ref.raw == raw {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretEnvKeys 프로젝트와 그 환경들의 env 중 secret: 참조로 설정한 변수 이름.
+// 환경끼리 같은 서버 경로를 쓸 수 있으므로 name 의 환경과 관계없이 모두 포함합니다
+func (c *Config) SecretEnvKeys(name string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	project, _ := SplitName(name)
+	prefixes := []string{"env."}
+	for env := range c.Projects[project].Environments {
+		prefixes = append(prefixes, "environments."+env+".env.")
+	}
+	seen := make(map[string]bool)
+	var keys []string
+	for path, ref := range c.refs[project] {
+		if !strings.HasPrefix(ref.raw, secretPrefix) {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if key, ok := strings.CutPrefix(path, prefix); ok && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "deploy_key")
+	if err := os.WriteFile(keyFile, []byte("-----BEGIN KEY-----\nabc\n-----END KEY-----\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SSHIP_TEST_PASSWORD", "s3cret")
+	t.Setenv("SSHIP_TEST_ROOT", "/srv")
+
+	path := filepath.Join(dir, "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: ${SSHIP_TEST_HOST:-10.0.0.1}
+      user: deploy
+      password: ${SSHIP_TEST_PASSWORD}
+      private_key: file:` + keyFile + `
+      jump:
+        - host: bastion
+          password: ${SSHIP_TEST_PASSWORD}
+    path: ${SSHIP_TEST_ROOT}/web
+    branch: main
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	proj, _ := cfg.GetProject("web")
+	if proj.Server.Host != "10.0.0.1" || proj.Server.Password != "s3cret" || proj.Path != "/srv/web" {
+		t.Errorf("참조가 해석되지 않음: host=%q password=%q path=%q", proj.Server.Host, proj.Server.Password, proj.Path)
+	}
+	if proj.Server.PrivateKey != "-----BEGIN KEY-----\nabc\n-----END KEY-----" {
+		t.Errorf("private_key = %q", proj.Server.PrivateKey)
+	}
+	if proj.Server.Jump[0].Password != "s3cret" {
+		t.Errorf("jump password = %q", proj.Server.Jump[0].Password)
+	}
+
+	// 바뀌지 않은 값은 참조로, 바뀐 값은 새 값으로 저장
+	proj.Branch = "release"
+	proj.Server.Host = "10.0.0.9"
+	cfg.SetProject("web", proj)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"password: ${SSHIP_TEST_PASSWORD}",
+		"private_key: file:" + keyFile,
+		"path: ${SSHIP_TEST_ROOT}/web",
+		"host: 10.0.0.9",
+		"branch: release",
+	} {
+		if !strings.Contains(string(saved), want) {
+			t.Errorf("저장된 설정에 %q 없음:\n%s", want, saved)
+		}
+	}
+	if strings.Contains(string(saved), "s3cret") {
+		t.Errorf("비밀번호가 평문으로 저장됨:\n%s", saved)
+	}
+	if proj, _ := cfg.GetProject("web"); proj.Server.Password != "s3cret" || proj.Server.Jump[0].Password != "s3cret" {
+		t.Error("Save 가 메모리의 설정을 바꿈")
+	}
+
+	os.Unsetenv("SSHIP_TEST_PASSWORD")
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "SSHIP_TEST_PASSWORD") {
+		t.Errorf("LoadConfig() error = %v, want 환경변수 누락 오류", err)
+	}
+}
+
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) Get(name string) (string, bool) {
+	value, ok := f[name]
+	return value, ok
+}
+
+func TestSecretReference(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+      password: secret:web-ssh
+    path: /srv/web
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	UseSecrets(nil)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("시크릿 저장소 없이 secret: 참조가 해석됨")
+	}
+
+	UseSecrets(fakeSecrets{"web-ssh": "s3cret"})
+	defer UseSecrets(nil)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if proj, _ := cfg.GetProject("web"); proj.Server.Password != "s3cret" {
+		t.Errorf("password = %q", proj.Server.Password)
+	}
+	if got := cfg.ProjectsUsingReference("secret:web-ssh"); len(got) != 1 || got[0] != "web" {
+		t.Errorf("ProjectsUsingReference() = %v", got)
+	}
+}
+
+func TestSecretReferenceUnusedServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `servers:
+  spare:
+    host: 10.0.0.9
+    password: secret:spare-ssh
+projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	UseSecrets(fakeSecrets{"spare-ssh": "s3cret"})
+	defer UseSecrets(nil)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	// 어떤 프로젝트도 쓰지 않는 서버의 참조도 찾아야 시크릿 삭제를 막을 수 있음
+	if got := cfg.ServersUsingReference("secret:spare-ssh"); len(got) != 1 || got[0] != "spare" {
+		t.Errorf("ServersUsingReference() = %v", got)
+	}
+	if got := cfg.ServersUsingReference("secret:other"); len(got) != 0 {
+		t.Errorf("ServersUsingReference(other) = %v", got)
+	}
+}
+
+func TestSecretReferenceAppEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+    path: /srv/web
+    env:
+      DATABASE_URL: secret:web-db
+      LOG_LEVEL: info
+    environments:
+      staging:
+        env:
+          LOG_LEVEL: debug
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	UseSecrets(fakeSecrets{"web-db": "postgres://app:s3cret@db/app"})
+	defer UseSecrets(nil)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	web, _ := cfg.GetProject("web")
+	if web.Env["DATABASE_URL"] != "postgres://app:s3cret@db/app" {
+		t.Errorf("env = %v", web.Env)
+	}
+	if got := cfg.ProjectsUsingReference("secret:web-db"); len(got) != 1 || got[0] != "web" {
+		t.Errorf("ProjectsUsingReference() = %v", got)
+	}
+
+	// 환경은 지정한 변수만 덮어씀
+	staging, _ := cfg.GetProject("web@staging")
+	if staging.Env["LOG_LEVEL"] != "debug" || staging.Env["DATABASE_URL"] != web.Env["DATABASE_URL"] {
+		t.Errorf("staging env = %v", staging.Env)
+	}
+
+	// 저장해도 시크릿 값 대신 참조가 남아야 함
+	web.Branch = "main"
+	cfg.SetProject("web", web)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "DATABASE_URL: secret:web-db") || strings.Contains(string(data), "s3cret") {
+		t.Errorf("저장된 설정:\n%s", data)
+	}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeFileAtomic 같은 디렉토리의 임시 파일에 쓴 뒤 rename 하여, 중간에 실패해도 기존 파일이 온전히 남도록 합니다.
+// 파일이 이미 있으면 기존 권한을 유지합니다
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// marshalPreserving out 을 YAML 로 만들되, 기존 파일 내용(existing)의 주석, 키 순서, 따옴표 스타일, 들여쓰기를 유지합니다.
+// 기존 파일에 없던 빈 값(빈 문자열, 0, false 등)은 쓰지 않습니다
+func marshalPreserving(out *Config, existing []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(out); err != nil {
+		return nil, err
+	}
+
+	var old yaml.Node
+	if len(existing) > 0 {
+		if err := yaml.Unmarshal(existing, &old); err != nil {
+			// 손상된 파일이면 기존 내용을 무시하고 새로 씀
+			old = yaml.Node{}
+		}
+	}
+	var oldRoot *yaml.Node
+	if old.Kind == yaml.DocumentNode && len(old.Content) > 0 {
+		oldRoot = old.Content[0]
+	}
+
+	merged := mergeNode(oldRoot, &doc, true)
+	// 빈 값을 생략해도 같은 설정으로 읽히는지 확인 (예: uid: 0 은 생략하면 의미가 바뀜)
+	var check Config
+	if err := merged.Decode(&check); err != nil || !reflect.DeepEqual(check.Projects, out.Projects) || !reflect.DeepEqual(check.Servers, out.Servers) {
+		merged = mergeNode(oldRoot, &doc, false)
+	}
+
+	root := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{merged}}
+	if old.Kind == yaml.DocumentNode {
+		root.HeadComment, root.LineComment, root.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(detectIndent(existing))
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeNode old 의 주석과 스타일을 유지하면서 new 의 내용을 반영한 노드. prune 이면 old 에 없던 빈 값을 생략합니다
+func mergeNode(old, new *yaml.Node, prune bool) *yaml.Node {
+	if old == nil {
+		if prune {
+			return pruneZero(new)
+		}
+		return new
+	}
+	if old.Kind != new.Kind {
+		n := *new
+		copyComments(&n, old)
+		if prune {
+			return pruneZero(&n)
+		}
+		return &n
+	}
+
+	switch new.Kind {
+	case yaml.ScalarNode:
+		if old.Value == new.Value && (old.Tag == new.Tag || old.Tag == "!!null") {
+			return old
+		}
+		n := *new
+		copyComments(&n, old)
+		// 따옴표는 어떤 값에도 안전하므로 기존 스타일을 유지
+		if n.Style == 0 && (old.Style == yaml.SingleQuotedStyle || old.Style == yaml.DoubleQuotedStyle) {
+			n.Style = old.Style
+		}
+		return &n
+
+	case yaml.MappingNode:
+		n := *old
+		n.Content = nil
+		for i := 0; i+1 < len(old.Content); i += 2 {
+			if value := mappingValue(new, old.Content[i].Value); value != nil {
+				n.Content = append(n.Content, old.Content[i], mergeNode(old.Content[i+1], value, prune))
+			}
+		}
+		for i := 0; i+1 < len(new.Content); i += 2 {
+			key, value := new.Content[i], new.Content[i+1]
+			if mappingValue(old, key.Value) != nil || (prune && isZeroNode(value)) {
+				continue
+			}
+			if prune {
+				value = pruneZero(value)
+			}
+			n.Content = append(n.Content, key, value)
+		}
+		return &n
+
+	case yaml.SequenceNode:
+		n := *old
+		n.Content = nil
+		for i, item := range new.Content {
+			if i < len(old.Content) {
+				n.Content = append(n.Content, mergeNode(old.Content[i], item, prune))
+			} else if prune {
+				n.Content = append(n.Content, pruneZero(item))
+			} else {
+				n.Content = append(n.Content, item)
+			}
+		}
+		return &n
+	}
+	return new
+}
+
+func copyComments(dst, src *yaml.Node) {
+	dst.HeadComment, dst.LineComment, dst.FootComment = src.HeadComment, src.LineComment, src.FootComment
+}
+
+// pruneZero 매핑에서 빈 값을 가진 키를 뺀 복사본
+func pruneZero(node *yaml.Node) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		n := *node
+		n.Content = nil
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !isZeroNode(node.Content[i+1]) {
+				n.Content = append(n.Content, node.Content[i], pruneZero(node.Content[i+1]))
+			}
+		}
+		return &n
+	case yaml.SequenceNode:
+		n := *node
+		n.Content = make([]*yaml.Node, len(node.Content))
+		for i, item := range node.Content {
+			n.Content[i] = pruneZero(item)
+		}
+		return &n
+	}
+	return node
+}
+
+func isZeroNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return true
+		case "!!str":
+			return node.Value == ""
+		case "!!int":
+			return node.Value == "0"
+		case "!!bool":
+			return node.Value == "false"
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if !isZeroNode(node.Content[i]) {
+				return false
+			}
+		}
+		return true
+	case yaml.SequenceNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// detectIndent 기존 파일의 들여쓰기 폭. 알 수 없으면 yaml.Marshal 기본값(4)
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent := len(line) - len(trimmed); indent >= 2 && indent <= 8 {
+			return indent
+		}
+		break
+	}
+	return 4
+}
+
+// readExisting 설정 파일의 현재 내용. 파일이 없으면 nil
+func readExisting(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("설정 파일을 읽을 수 없습니다: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// 여러 프로젝트가 같은 서버를 쓰면 servers 에 한 번 정의하고 이름으로 참조합니다
+//
+//	servers:
+//	  vps1:
+//	    host: 10.0.0.1
+//	    user: deploy
+//	    password: secret:vps1-password
+//	projects:
+//	  web:
+//	    server: vps1
+//	    path: /srv/web
+//
+// 기존의 인라인 server 블록은 로드할 때 servers 로 옮겨지고, 다음 저장부터 이름 참조로 기록됩니다.
+
+var (
+	ErrServerNotFound = errors.New("서버를 찾을 수 없습니다")
+	ErrServerInUse    = errors.New("서버를 사용하는 프로젝트가 있습니다")
+)
+
+var (
+	// 서버, 환경 이름에 쓸 수 있는 문자
+	namePattern       = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+	serverNameInvalid = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// ValidateServerName 서버 이름은 영문, 숫자, '_', '.', '-' 로 128자 이하
+func ValidateServerName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("잘못된 서버 이름입니다 (영문, 숫자, _ . - 만 사용): %q", name)
+	}
+	return nil
+}
+
+// UnmarshalYAML server 에 servers 의 이름(문자열)이나 접속 설정(매핑)을 모두 받습니다
+func (p *Project) UnmarshalYAML(node *yaml.Node) error {
+	type plain Project
+	name, err := decodeServerName(node, (*plain)(p))
+	if name != "" {
+		p.ServerName = name
+	}
+	return err
+}
+
+// MarshalYAML ServerName 이 있으면 접속 설정 대신 서버 이름을 씁니다
+func (p Project) MarshalYAML() (interface{}, error) {
+	type plain Project
+	if p.ServerName == "" {
+		return plain(p), nil
+	}
+	p.Server = ssh.ConnectionConfig{}
+	return encodeServerName(plain(p), p.ServerName)
+}
+
+// decodeServerName server 가 문자열이면 그 키를 빼고 out 에 디코딩한 뒤 서버 이름을 반환합니다
+func decodeServerName(node *yaml.Node, out interface{}) (string, error) {
+	server := mappingValue(node, "server")
+	if server == nil || server.Kind != yaml.ScalarNode || server.Tag == "!!null" {
+		return "", node.Decode(out)
+	}
+
+	rest := *node
+	rest.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "server" {
+			rest.Content = append(rest.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	return server.Value, rest.Decode(out)
+}
+
+// encodeServerName v 를 노드로 만들고 server 값을 서버 이름으로 바꿉니다
+func encodeServerName(v interface{}, serverName string) (interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	if server := mappingValue(&node, "server"); server != nil {
+		*server = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: serverName}
+	} else {
+		node.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "server"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: serverName},
+		}, node.Content...)
+	}
+	return &node, nil
+}
+
+// splitServerRefs 프로젝트 참조에서 인라인 server 블록의 참조를 서버 기준 경로로 분리합니다
+func splitServerRefs(refs map[string]reference) (project, server map[string]reference) {
+	project = make(map[string]reference)
+	server = make(map[string]reference)
+	for path, ref := range refs {
+		if rest, ok := strings.CutPrefix(path, "server."); ok {
+			server[rest] = ref
+		} else {
+			project[path] = ref
+		}
+	}
+	return project, server
+}
+
+func (c *Config) resolveServerLocked(p Project) Project {
+	if p.ServerName != "" {
+		p.Server = c.Servers[p.ServerName]
+	}
+	return p
+}
+
+// setServerLocked SetProject 로 받은 프로젝트의 접속 설정을 servers 에 반영합니다
+func (c *Config) setServerLocked(project string, p *Project) {
+	if p.ServerName == "" {
+		c.attachServerLocked(p, nil)
+		return
+	}
+
+	current, ok := c.Servers[p.ServerName]
+	switch {
+	case p.Server.Host == "" || reflect.DeepEqual(current, p.Server):
+	case !ok:
+		c.initServersLocked()
+		c.Servers[p.ServerName] = p.Server
+	default:
+		// 접속 설정이 바뀜: 이 프로젝트만 쓰는 서버면 그대로 수정하고, 아니면 다른 프로젝트에 영향이 없도록 분리
+		users := c.projectsUsingServerLocked(p.ServerName)
+		if len(users) == 0 || (len(users) == 1 && users[0] == project) {
+			c.Servers[p.ServerName] = p.Server
+			break
+		}
+		refs := c.serverRefs[p.ServerName]
+		p.ServerName = ""
+		c.attachServerLocked(p, refs)
+		return
+	}
+	p.Server = ssh.ConnectionConfig{}
+}
+
+// attachServerLocked 인라인 접속 설정을 같은 설정의 서버에 연결하거나, 호스트 이름으로 새 서버를 만듭니다
+func (c *Config) attachServerLocked(p *Project, refs map[string]reference) {
+	if p.Server.Host == "" {
+		return
+	}
+	c.initServersLocked()
+
+	for _, name := range c.serverNamesLocked() {
+		if reflect.DeepEqual(c.Servers[name], p.Server) && sameReferences(c.serverRefs[name], refs) {
+			p.ServerName, p.Server = name, ssh.ConnectionConfig{}
+			return
+		}
+	}
+
+	base := serverNameInvalid.ReplaceAllString(p.Server.Host, "-")
+	name := base
+	for i := 2; ; i++ {
+		if _, exists := c.Servers[name]; !exists {
+			break
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	c.Servers[name] = p.Server
+	if len(refs) > 0 {
+		c.serverRefs[name] = refs
+	}
+	p.ServerName, p.Server = name, ssh.ConnectionConfig{}
+}
+
+func sameReferences(a, b map[string]reference) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (c *Config) initServersLocked() {
+	if c.Servers == nil {
+		c.Servers = make(map[string]ssh.ConnectionConfig)
+	}
+	if c.serverRefs == nil {
+		c.serverRefs = make(map[string]map[string]reference)
+	}
+}
+
+func (c *Config) serverNamesLocked() []string {
+	names := make([]string, 0, len(c.Servers))
+	for name := range c.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) GetServers() map[string]ssh.ConnectionConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	servers := make(map[string]ssh.ConnectionConfig, len(c.Servers))
+	for k, v := range c.Servers {
+		servers[k] = v
+	}
+	return servers
+}
+
+func (c *Config) GetServer(name string) (ssh.ConnectionConfig, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.Servers[name]
+	return s, ok
+}
+
+// SetServer 서버를 만들거나 수정합니다. 이 서버를 참조하는 모든 프로젝트에 바로 반영됩니다
+func (c *Config) SetServer(name string, server ssh.ConnectionConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.initServersLocked()
+	c.Servers[name] = server
+}
+
+// DeleteServer 서버를 삭제합니다. 참조하는 프로젝트가 있으면 ErrServerInUse
+func (c *Config) DeleteServer(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.Servers[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrServerNotFound, name)
+	}
+	if users := c.projectsUsingServerLocked(name); len(users) > 0 {
+		return fmt.Errorf("%w: %s", ErrServerInUse, strings.Join(users, ", "))
+	}
+	delete(c.Servers, name)
+	delete(c.serverRefs, name)
+	return nil
+}
+
+// ProjectsUsingServer 서버를 참조하는 프로젝트 이름
+func (c *Config) ProjectsUsingServer(name string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.projectsUsingServerLocked(name)
+}
+
+func (c *Config) projectsUsingServerLocked(name string) []string {
+	var names []string
+	for projectName, proj := range c.Projects {
+		if proj.ServerName == name {
+			names = append(names, projectName)
+		}
+		for envName, env := range proj.Environments {
+			if env.ServerName == name {
+				names = append(names, QualifiedName(projectName, envName))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
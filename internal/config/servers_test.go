@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServerMigration(t *testing.T) {
+	t.Setenv("SSHIP_TEST_PASSWORD", "s3cret")
+
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	content := `projects:
+  api:
+    server:
+      host: 10.0.0.1
+      user: deploy
+      password: ${SSHIP_TEST_PASSWORD}
+    path: /srv/api
+  web:
+    server:
+      host: 10.0.0.1
+      user: deploy
+      password: ${SSHIP_TEST_PASSWORD}
+    path: /srv/web
+  admin:
+    server:
+      host: 10.0.0.1
+      user: root
+    path: /srv/admin
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got := cfg.ProjectsUsingServer("10.0.0.1-2"); strings.Join(got, ",") != "api,web" {
+		t.Errorf("같은 접속 설정이 하나의 서버로 합쳐지지 않음: %v (servers=%v)", got, cfg.GetServers())
+	}
+	if got := cfg.ProjectsUsingServer("10.0.0.1"); strings.Join(got, ",") != "admin" {
+		t.Errorf("ProjectsUsingServer(10.0.0.1) = %v", got)
+	}
+	if proj, _ := cfg.GetProject("web"); proj.Server.Password != "s3cret" || proj.Server.Port != 22 || proj.ServerName != "10.0.0.1-2" {
+		t.Errorf("GetProject() server = %+v (%s)", proj.Server, proj.ServerName)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(saved), "password: ${SSHIP_TEST_PASSWORD}"); n != 1 {
+		t.Errorf("비밀번호 참조가 %d 번 저장됨:\n%s", n, saved)
+	}
+	if !strings.Contains(string(saved), "server: 10.0.0.1-2") {
+		t.Errorf("프로젝트가 서버 이름으로 저장되지 않음:\n%s", saved)
+	}
+
+	// 다시 읽어도 같은 설정
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v\n%s", err, saved)
+	}
+	if change := diffProjects(cfg.GetProjects(), reloaded.GetProjects()); !change.Empty() {
+		t.Errorf("저장 후 다시 읽은 설정이 다름: %s", change)
+	}
+
+	// 서버 하나를 고치면 참조하는 모든 프로젝트에 반영
+	server, _ := cfg.GetServer("10.0.0.1-2")
+	server.User = "app"
+	cfg.SetServer("10.0.0.1-2", server)
+	for _, name := range []string{"api", "web"} {
+		if proj, _ := cfg.GetProject(name); proj.Server.User != "app" {
+			t.Errorf("%s: user = %q", name, proj.Server.User)
+		}
+	}
+
+	// 공유 서버를 쓰는 프로젝트의 접속 설정만 바꾸면 다른 프로젝트에 영향 없이 분리
+	proj, _ := cfg.GetProject("web")
+	proj.Server.Port = 2222
+	cfg.SetProject("web", proj)
+	if proj, _ := cfg.GetProject("api"); proj.Server.Port != 22 {
+		t.Errorf("다른 프로젝트의 서버가 바뀜: port = %d", proj.Server.Port)
+	}
+	if proj, _ := cfg.GetProject("web"); proj.Server.Port != 2222 || proj.ServerName == "10.0.0.1-2" {
+		t.Errorf("분리된 서버 = %+v (%s)", proj.Server, proj.ServerName)
+	}
+
+	if err := cfg.DeleteServer("10.0.0.1-2"); !errors.Is(err, ErrServerInUse) {
+		t.Errorf("DeleteServer() error = %v, want ErrServerInUse", err)
+	}
+	cfg.DeleteProject("api")
+	if err := cfg.DeleteServer("10.0.0.1-2"); err != nil {
+		t.Errorf("DeleteServer() error = %v", err)
+	}
+}
+
+func TestServerReferenceValidation(t *testing.T) {
+	content := `servers:
+  vps1:
+    host: 10.0.0.1
+projects:
+  web:
+    server: vps2
+    path: /srv/web
+`
+	err := Validate([]byte(content))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 6 || !strings.Contains(errs[0].Message, "정의되지 않은 서버") {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	if err := Validate([]byte(strings.Replace(content, "vps2", "vps1", 1))); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
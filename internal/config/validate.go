@@ -0,0 +1,461 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError sship.yaml 의 한 위치에서 발견된 문제
+type ValidationError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// ValidationErrors 위치 순으로 정렬된 검증 오류 목록
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	durationType         = reflect.TypeOf(time.Duration(0))
+	connectionConfigType = reflect.TypeOf(ssh.ConnectionConfig{})
+	hookType             = reflect.TypeOf(Hook{})
+	// yaml.v3 오류 메시지의 "line N: ..." 형식
+	yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	envNamePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validate sship.yaml 내용을 검사합니다. 알 수 없는 필드, 잘못된 타입과 함께
+// 포트 범위, 절대 경로, 브랜치 이름, 헬스체크 URL 등을 확인하며, 문제가 있으면 ValidationErrors 를 반환합니다.
+func Validate(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return ValidationErrors{yamlError(err.Error())}
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	v := &validation{}
+	doc := root.Content[0]
+	v.walk(doc, reflect.TypeOf(Config{}), "")
+	if len(v.errs) == 0 {
+		// 구조 검사에서 놓친 타입 오류
+		var cfg Config
+		var typeErr *yaml.TypeError
+		if err := doc.Decode(&cfg); errors.As(err, &typeErr) {
+			for _, msg := range typeErr.Errors {
+				v.errs = append(v.errs, yamlError(msg))
+			}
+		} else if err != nil {
+			v.errs = append(v.errs, yamlError(err.Error()))
+		}
+	}
+	if len(v.errs) == 0 {
+		servers := mappingValue(doc, "servers")
+		v.checkServers(servers)
+		v.checkProjects(mappingValue(doc, "projects"), servers)
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		if v.errs[i].Line != v.errs[j].Line {
+			return v.errs[i].Line < v.errs[j].Line
+		}
+		return v.errs[i].Column < v.errs[j].Column
+	})
+	return v.errs
+}
+
+func yamlError(msg string) ValidationError {
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return ValidationError{Line: line, Message: m[2]}
+	}
+	return ValidationError{Message: strings.TrimPrefix(msg, "yaml: ")}
+}
+
+type validation struct {
+	errs ValidationErrors
+}
+
+func (v *validation) add(node *yaml.Node, path string, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// walk 노드 구조가 t 와 맞는지 확인합니다 (알 수 없는 필드, 매핑/목록/스칼라 종류, 숫자/불리언/기간 값)
+func (v *validation) walk(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == connectionConfigType && node.Kind == yaml.ScalarNode:
+		// 프로젝트의 server: NAME 참조. 서버가 있는지는 checkProjects 에서, 그 밖의 위치는 Decode 에서 확인
+		return
+
+	case t == hookType && node.Kind == yaml.ScalarNode:
+		// 훅의 문자열 형식 (run 만 지정)
+		return
+
+	case t == durationType:
+		if node.Kind != yaml.ScalarNode {
+			v.add(node, path, "기간 값이어야 합니다 (예: 5m, 90s)")
+		} else if _, err := time.ParseDuration(node.Value); err != nil && node.Tag != "!!int" {
+			v.add(node, path, "잘못된 기간 값입니다: %q (예: 5m, 90s)", node.Value)
+		}
+
+	case t.Kind() == reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, "매핑이어야 합니다")
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			ft, ok := fields[key.Value]
+			if !ok {
+				if suggestion := closestField(key.Value, fields); suggestion != "" {
+					v.add(key, joinPath(path, key.Value), "알 수 없는 필드입니다 (%s 을(를) 의도했나요?)", suggestion)
+				} else {
+					v.add(key, joinPath(path, key.Value), "알 수 없는 필드입니다")
+				}
+				continue
+			}
+			v.walk(value, ft, joinPath(path, key.Value))
+		}
+
+	case t.Kind() == reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, "매핑이어야 합니다")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.walk(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))
+		}
+
+	case t.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.add(node, path, "목록이어야 합니다")
+			return
+		}
+		for i, item := range node.Content {
+			v.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			v.add(node, path, "정수여야 합니다")
+		}
+
+	case t.Kind() == reflect.Bool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			v.add(node, path, "true 또는 false 여야 합니다")
+		}
+
+	case t.Kind() == reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			v.add(node, path, "문자열이어야 합니다")
+		}
+	}
+}
+
+// yamlName 필드의 yaml 키. 직렬화되지 않는 필드면 ok 가 false
+func yamlName(f reflect.StructField) (name string, inline bool, ok bool) {
+	tag := f.Tag.Get("yaml")
+	if f.PkgPath != "" || tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name, strings.Contains(opts, "inline"), true
+}
+
+// yamlFields 구조체의 yaml 키와 필드 타입 (inline 필드 포함)
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, inline, ok := yamlName(f)
+		if !ok {
+			continue
+		}
+		if inline {
+			for k, ft := range yamlFields(f.Type) {
+				fields[k] = ft
+			}
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// closestField 오타로 보이는 키와 편집 거리가 2 이하인 필드 이름
+func closestField(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// mappingValue 매핑 노드에서 key 의 값 노드. 없으면 nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func (v *validation) checkServers(servers *yaml.Node) {
+	if servers == nil || servers.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(servers.Content); i += 2 {
+		key, server := servers.Content[i], servers.Content[i+1]
+		p := joinPath("servers", key.Value)
+		if err := ValidateServerName(key.Value); err != nil {
+			v.add(key, p, "%v", err)
+		}
+		if server.Kind != yaml.MappingNode {
+			v.add(server, p, "서버 설정이 비어 있습니다")
+			continue
+		}
+		v.checkServer(server, p)
+	}
+}
+
+func (v *validation) checkProjects(projects, servers *yaml.Node) {
+	if projects == nil || projects.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(projects.Content); i += 2 {
+		key, proj := projects.Content[i], projects.Content[i+1]
+		p := joinPath("projects", key.Value)
+		if strings.Contains(key.Value, EnvSeparator) {
+			v.add(key, p, "프로젝트 이름에 %q 를 쓸 수 없습니다", EnvSeparator)
+		}
+		if proj.Kind != yaml.MappingNode {
+			v.add(proj, p, "프로젝트 설정이 비어 있습니다")
+			continue
+		}
+
+		if mappingValue(proj, "server") == nil {
+			v.add(key, p, "server 가 설정되지 않았습니다")
+		}
+		if node := mappingValue(proj, "path"); node == nil || node.Value == "" {
+			v.add(key, joinPath(p, "path"), "프로젝트 경로가 설정되지 않았습니다")
+		}
+		v.checkProject(proj, p, servers)
+
+		if envs := mappingValue(proj, "environments"); envs != nil && envs.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(envs.Content); j += 2 {
+				envKey, env := envs.Content[j], envs.Content[j+1]
+				ep := joinPath(joinPath(p, "environments"), envKey.Value)
+				if err := ValidateEnvironmentName(envKey.Value); err != nil {
+					v.add(envKey, ep, "%v", err)
+				}
+				v.checkProject(env, ep, servers)
+			}
+		}
+	}
+}
+
+// checkProject 프로젝트나 환경에 설정된 필드의 값을 확인합니다
+func (v *validation) checkProject(proj *yaml.Node, p string, servers *yaml.Node) {
+	if server := mappingValue(proj, "server"); server != nil && server.Kind == yaml.ScalarNode && server.Tag != "!!null" {
+		if mappingValue(servers, server.Value) == nil {
+			v.add(server, joinPath(p, "server"), "정의되지 않은 서버입니다: %q", server.Value)
+		}
+	} else if server != nil {
+		v.checkServer(server, joinPath(p, "server"))
+	}
+
+	if node := mappingValue(proj, "path"); node != nil && node.Value != "" && !isReference(node.Value) && !path.IsAbs(node.Value) {
+		v.add(node, joinPath(p, "path"), "절대 경로여야 합니다: %q", node.Value)
+	}
+
+	if node := mappingValue(proj, "branch"); node != nil && node.Value != "" && !isReference(node.Value) {
+		if err := ssh.ValidateBranch(node.Value); err != nil {
+			v.add(node, joinPath(p, "branch"), "%v", err)
+		}
+	}
+
+	if node := mappingValue(proj, "health_check"); node != nil && node.Value != "" && !isReference(node.Value) {
+		if err := validURL(node.Value); err != nil {
+			v.add(node, joinPath(p, "health_check"), "%v", err)
+		}
+	}
+
+	if node := mappingValue(proj, "port"); node != nil {
+		v.checkPort(node, joinPath(p, "port"))
+	}
+
+	if files := mappingValue(proj, "files"); files != nil {
+		for j, file := range files.Content {
+			fp := fmt.Sprintf("%s[%d]", joinPath(p, "files"), j)
+			if node := mappingValue(file, "mode"); node != nil && node.Value != "" && !isReference(node.Value) {
+				if _, err := strconv.ParseUint(node.Value, 8, 32); err != nil {
+					v.add(node, joinPath(fp, "mode"), "8진수 권한이어야 합니다 (예: \"0600\"): %q", node.Value)
+				}
+			}
+		}
+	}
+
+	if env := mappingValue(proj, "env"); env != nil && env.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(env.Content); i += 2 {
+			if key := env.Content[i]; !envNamePattern.MatchString(key.Value) {
+				v.add(key, joinPath(joinPath(p, "env"), key.Value), "환경변수 이름은 영문자, 숫자, _ 만 쓸 수 있습니다: %q", key.Value)
+			}
+		}
+	}
+
+	if hooks := mappingValue(proj, "hooks"); hooks != nil && hooks.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(hooks.Content); i += 2 {
+			hp := joinPath(joinPath(p, "hooks"), hooks.Content[i].Value)
+			for j, hook := range hooks.Content[i+1].Content {
+				run := hook
+				if hook.Kind == yaml.MappingNode {
+					run = mappingValue(hook, "run")
+				}
+				if run == nil || strings.TrimSpace(run.Value) == "" {
+					v.add(hook, fmt.Sprintf("%s[%d]", hp, j), "실행할 명령이 비어 있습니다")
+				}
+			}
+		}
+	}
+}
+
+func (v *validation) checkServer(server *yaml.Node, p string) {
+	if server.Kind != yaml.MappingNode {
+		return
+	}
+	if node := mappingValue(server, "host"); node == nil || node.Value == "" {
+		v.add(server, joinPath(p, "host"), "서버 호스트가 설정되지 않았습니다")
+	}
+	if node := mappingValue(server, "port"); node != nil {
+		v.checkPort(node, joinPath(p, "port"))
+	}
+	if node := mappingValue(server, "runtime"); node != nil && node.Value != "" && !isReference(node.Value) {
+		switch node.Value {
+		case ssh.RuntimeDocker, ssh.RuntimeDockerCompose, ssh.RuntimePodmanCompose, ssh.RuntimePodman:
+		default:
+			v.add(node, joinPath(p, "runtime"), "알 수 없는 컨테이너 런타임입니다: %q", node.Value)
+		}
+	}
+	if node := mappingValue(server, "auth_order"); node != nil {
+		for _, item := range node.Content {
+			if isReference(item.Value) {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(item.Value)) {
+			case ssh.AuthAgent, ssh.AuthKey, ssh.AuthPassword:
+			default:
+				v.add(item, joinPath(p, "auth_order"), "알 수 없는 인증 방식입니다: %q", item.Value)
+			}
+		}
+	}
+	if jumps := mappingValue(server, "jump"); jumps != nil {
+		for i, jump := range jumps.Content {
+			v.checkServer(jump, fmt.Sprintf("%s[%d]", joinPath(p, "jump"), i))
+		}
+	}
+}
+
+// checkPort 포트 번호 범위 확인. 0 은 설정하지 않은 것으로 봅니다
+func (v *validation) checkPort(node *yaml.Node, p string) {
+	port, err := strconv.Atoi(node.Value)
+	if err != nil || port < 0 || port > 65535 {
+		v.add(node, p, "포트는 1-65535 범위여야 합니다 (0 이면 기본값): %s", node.Value)
+	}
+}
+
+func validURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("잘못된 헬스체크 URL 입니다: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("헬스체크 URL 은 http 또는 https 여야 합니다: %q", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("헬스체크 URL 에 호스트가 없습니다: %q", raw)
+	}
+	return nil
+}
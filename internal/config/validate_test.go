@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	data := []byte(`projects:
+  web:
+    server:
+      host: 10.0.0.1
+      port: 70000
+      user: deploy
+    path: srv/web
+    branch: feature..x
+    docker_compse: compose.yml
+    health_check: "localhost:8080/health"
+    timeouts:
+      build: 10 minutes
+  api:
+    server:
+      user: deploy
+      runtime: dokcer
+    path: /srv/api
+    port: abc
+`)
+
+	err := Validate(data)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+	}
+
+	type loc struct {
+		Line, Column int
+		Path         string
+	}
+	var got []loc
+	for _, e := range errs {
+		got = append(got, loc{e.Line, e.Column, e.Path})
+	}
+	// 구조 오류가 있으면 의미 검사는 건너뜀
+	want := []loc{
+		{9, 5, "projects.web.docker_compse"},
+		{12, 14, "projects.web.timeouts.build"},
+		{18, 11, "projects.api.port"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v\nwant %v", errs, want)
+	}
+	if errs[0].Message != "알 수 없는 필드입니다 (docker_compose 을(를) 의도했나요?)" {
+		t.Errorf("message = %q", errs[0].Message)
+	}
+
+	semantic := []byte(`projects:
+  web:
+    server:
+      host: 10.0.0.1
+      port: 70000
+    path: srv/web
+    branch: feature..x
+    health_check: "localhost:8080/health"
+  api:
+    server:
+      user: deploy
+      runtime: dokcer
+    path: /srv/api
+`)
+	if !errors.As(Validate(semantic), &errs) {
+		t.Fatal("의미 오류가 검출되지 않음")
+	}
+	got = nil
+	for _, e := range errs {
+		got = append(got, loc{e.Line, e.Column, e.Path})
+	}
+	want = []loc{
+		{5, 13, "projects.web.server.port"},
+		{6, 11, "projects.web.path"},
+		{7, 13, "projects.web.branch"},
+		{8, 19, "projects.web.health_check"},
+		{11, 7, "projects.api.server.host"},
+		{12, 16, "projects.api.server.runtime"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v\nwant %v", errs, want)
+	}
+	if errs[0].Message != "포트는 1-65535 범위여야 합니다 (0 이면 기본값): 70000" {
+		t.Errorf("message = %q", errs[0].Message)
+	}
+
+	if err := Validate([]byte(watchTestConfig)); err != nil {
+		t.Errorf("올바른 설정에서 오류: %v", err)
+	}
+	if err := Validate([]byte("projects: [\n")); err == nil {
+		t.Error("YAML 문법 오류가 검출되지 않음")
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change Reload 로 추가/삭제/변경된 프로젝트 이름
+type Change struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+}
+
+func (c Change) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Updated) == 0
+}
+
+func (c Change) String() string {
+	if c.Empty() {
+		return "변경된 프로젝트 없음"
+	}
+	var parts []string
+	if len(c.Added) > 0 {
+		parts = append(parts, "추가: "+strings.Join(c.Added, ", "))
+	}
+	if len(c.Updated) > 0 {
+		parts = append(parts, "변경: "+strings.Join(c.Updated, ", "))
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, "삭제: "+strings.Join(c.Removed, ", "))
+	}
+	return strings.Join(parts, " / ")
+}
+
+func diffProjects(old, new map[string]Project) Change {
+	var change Change
+	for name, proj := range new {
+		prev, ok := old[name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, name)
+		case !reflect.DeepEqual(prev, proj):
+			change.Updated = append(change.Updated, name)
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			change.Removed = append(change.Removed, name)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Updated)
+	sort.Strings(change.Removed)
+	return change
+}
+
+// Reload 설정 파일을 다시 읽어 검증한 뒤 프로젝트 목록을 한 번에 교체합니다.
+// 파일이 잘못되었으면 기존 설정을 그대로 유지합니다. 진행 중인 배포는 시작할 때 읽은 설정을 계속 사용합니다.
+func (c *Config) Reload() (Change, error) {
+	c.mu.RLock()
+	path := c.filePath
+	c.mu.RUnlock()
+	if path == "" {
+		return Change{}, fmt.Errorf("설정 파일 경로가 지정되지 않았습니다")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Change{}, fmt.Errorf("설정 파일을 읽을 수 없습니다: %v", err)
+	}
+	if err := Validate(data); err != nil {
+		return Change{}, fmt.Errorf("설정 검증 실패:\n%w", err)
+	}
+	loaded, err := parseConfig(data)
+	if err != nil {
+		return Change{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fileHash = sha256.Sum256(data)
+	// 직접 편집한 내용도 이력에 남김
+	if err := c.recordRevisionLocked(data); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+	return c.replaceLocked(loaded), nil
+}
+
+// replaceLocked 새로 읽은 설정으로 서버와 프로젝트 목록을 한 번에 교체합니다
+func (c *Config) replaceLocked(loaded *Config) Change {
+	change := diffProjects(c.resolvedProjectsLocked(), loaded.resolvedProjectsLocked())
+	c.Servers = loaded.Servers
+	c.Projects = loaded.Projects
+	c.refs = loaded.refs
+	c.serverRefs = loaded.serverRefs
+	return change
+}
+
+// Watch interval 마다 설정 파일을 확인하여 내용이 바뀌면 Reload 하고 결과를 onReload 로 전달합니다.
+// sship 이 직접 Save 한 내용은 무시합니다. ctx 가 끝나면 멈춥니다.
+func (c *Config) Watch(ctx context.Context, interval time.Duration, onReload func(Change, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastMod time.Time
+	var lastSize int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.RLock()
+		path, hash := c.filePath, c.fileHash
+		c.mu.RUnlock()
+
+		info, err := os.Stat(path)
+		if err != nil || (info.ModTime().Equal(lastMod) && info.Size() == lastSize) {
+			continue
+		}
+		lastMod, lastSize = info.ModTime(), info.Size()
+
+		data, err := os.ReadFile(path)
+		if err != nil || sha256.Sum256(data) == hash {
+			continue
+		}
+		onReload(c.Reload())
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const watchTestConfig = `projects:
+  web:
+    server:
+      host: 10.0.0.1
+      user: deploy
+    path: /srv/web
+  api:
+    server:
+      host: 10.0.0.2
+      user: deploy
+    path: /srv/api
+`
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	if err := os.WriteFile(path, []byte(watchTestConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	updated := `projects:
+  web:
+    server:
+      host: 10.0.0.1
+      user: deploy
+    path: /srv/web
+    branch: release
+  worker:
+    server:
+      host: 10.0.0.3
+      user: deploy
+    path: /srv/worker
+`
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	change, err := cfg.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	want := Change{Added: []string{"worker"}, Removed: []string{"api"}, Updated: []string{"web"}}
+	if !reflect.DeepEqual(change, want) {
+		t.Errorf("Reload() = %+v, want %+v", change, want)
+	}
+	if proj, _ := cfg.GetProject("web"); proj.Branch != "release" || proj.DockerCompose != "docker-compose.prod.yml" {
+		t.Errorf("web = %+v", proj)
+	}
+
+	// 잘못된 파일은 적용하지 않음
+	if err := os.WriteFile(path, []byte("projects:\n  web:\n    path: /srv/web\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Reload(); err == nil {
+		t.Fatal("서버 호스트가 없는 설정이 적용됨")
+	}
+	if len(cfg.GetProjects()) != 2 {
+		t.Errorf("실패한 Reload 가 설정을 바꿈: %+v", cfg.GetProjects())
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship.yaml")
+	if err := os.WriteFile(path, []byte(watchTestConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan Change, 10)
+	go cfg.Watch(ctx, 10*time.Millisecond, func(change Change, err error) {
+		if err != nil {
+			t.Errorf("Watch() error = %v", err)
+		}
+		changes <- change
+	})
+
+	// sship 이 직접 저장한 내용은 다시 로드하지 않음
+	time.Sleep(50 * time.Millisecond)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case change := <-changes:
+		t.Fatalf("Save 후 다시 로드됨: %+v", change)
+	default:
+	}
+
+	if err := os.WriteFile(path, []byte(watchTestConfig+"    branch: develop\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case change := <-changes:
+		if !reflect.DeepEqual(change.Updated, []string{"api"}) {
+			t.Errorf("change = %+v", change)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("파일 변경이 감지되지 않음")
+	}
+}
@@ -1,16 +1,28 @@
 package deploy
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/lambda0x63/sship/internal/config"
 	"github.com/lambda0x63/sship/internal/ssh"
 )
 
+// 프로젝트에 timeouts 가 지정되지 않은 경우의 단계별 제한 시간
+const (
+	defaultPullTimeout   = 5 * time.Minute
+	defaultBuildTimeout  = 30 * time.Minute
+	defaultHealthTimeout = 2 * time.Minute
+)
+
 type Deployer struct {
-	config *config.Config
+	config    *config.Config
+	connector ssh.Connector
 }
 
 type DeployResult struct {
@@ -28,53 +40,113 @@ type DeployProgress struct {
 	Status  string
 }
 
-func NewDeployer(cfg *config.Config) *Deployer {
+func NewDeployer(cfg *config.Config, connector ssh.Connector) *Deployer {
 	return &Deployer{
-		config: cfg,
+		config:    cfg,
+		connector: connector,
 	}
 }
 
-func (d *Deployer) Deploy(projectName string) error {
+func (d *Deployer) Deploy(ctx context.Context, projectName string) error {
+	return d.DeployTo(ctx, projectName, io.Discard)
+}
+
+// DeployTo Deploy 와 같지만 훅과 파일 업로드의 출력을 output 으로 보냅니다
+func (d *Deployer) DeployTo(ctx context.Context, projectName string, output io.Writer) error {
+	// 시작하기 전에 취소된 작업은 서버에 연결하지 않음
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	proj, exists := d.config.GetProject(projectName)
 	if !exists {
 		return fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
+	client, err := d.connector.Connect(proj.Server)
 	if err != nil {
 		return fmt.Errorf("SSH 연결 실패: %v", err)
 	}
 	defer client.Close()
 
-	if err := client.CreateBackup(proj.Path); err != nil {
+	if err := deploy(ctx, client, proj, output); err != nil {
+		runFailureHooks(ctx, client, proj, output)
+		return err
+	}
+	return nil
+}
+
+func deploy(ctx context.Context, client ssh.RemoteExecutor, proj config.Project, output io.Writer) error {
+	if err := client.CreateBackup(ctx, proj.Path); err != nil {
 		fmt.Printf("백업 실패 (계속 진행): %v\n", err)
 	}
 
-	if err := client.GitPull(proj.Path, proj.Branch); err != nil {
-		return fmt.Errorf("Git pull 실패: %v", err)
+	if err := runHooks(ctx, client, proj, hookPrePull, proj.Hooks.PrePull, output); err != nil {
+		return err
+	}
+	pullCtx, cancel := stepContext(ctx, proj.Timeouts.Pull, defaultPullTimeout)
+	err := client.GitPull(pullCtx, proj.Path, proj.Branch)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("Git pull 실패: %w", err)
+	}
+	if err := runHooks(ctx, client, proj, hookPostPull, proj.Hooks.PostPull, output); err != nil {
+		return err
 	}
 
-	if err := client.DockerComposeUp(proj.Path, proj.DockerCompose); err != nil {
-		return fmt.Errorf("Docker Compose 실행 실패: %v", err)
+	if err := uploadFiles(ctx, client, proj, output); err != nil {
+		return err
+	}
+	if err := writeEnvFile(ctx, client, proj, output); err != nil {
+		return err
 	}
 
-	return nil
+	if err := runHooks(ctx, client, proj, hookPreUp, proj.Hooks.PreUp, output); err != nil {
+		return err
+	}
+	buildCtx, cancel := stepContext(ctx, proj.Timeouts.Build, defaultBuildTimeout)
+	err = client.DockerComposeUp(buildCtx, proj.Path, proj.DockerCompose)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("Docker Compose 실행 실패: %w", err)
+	}
+
+	if proj.HealthCheck != "" {
+		fmt.Fprintf(output, "🔍 헬스체크 시작: %s\n", proj.HealthCheck)
+		healthCtx, cancel := stepContext(ctx, proj.Timeouts.Health, defaultHealthTimeout)
+		status, err := waitHealthy(healthCtx, client, proj.HealthCheck)
+		cancel()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(output, "💚 헬스체크 통과: HTTP %d\n", status)
+	}
+
+	// 헬스체크를 통과한 뒤 실행 (캐시 워밍 등)
+	return runHooks(ctx, client, proj, hookPostUp, proj.Hooks.PostUp, output)
 }
 
-func (d *Deployer) DeployWithProgress(projectName string, output io.Writer, progressChan chan<- DeployProgress) error {
+func (d *Deployer) DeployWithProgress(ctx context.Context, projectName string, output io.Writer, progressChan chan<- DeployProgress) error {
 	proj, exists := d.config.GetProject(projectName)
 	if !exists {
 		return fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
+	client, err := d.connector.Connect(proj.Server)
 	if err != nil {
 		return fmt.Errorf("SSH 연결 실패: %v", err)
 	}
 	defer client.Close()
 
+	if err := deployWithProgress(ctx, client, proj, output, progressChan); err != nil {
+		runFailureHooks(ctx, client, proj, output)
+		return err
+	}
+	return nil
+}
+
+func deployWithProgress(ctx context.Context, client ssh.RemoteExecutor, proj config.Project, output io.Writer, progressChan chan<- DeployProgress) error {
 	progressChan <- DeployProgress{Step: "connect", Message: "서버 연결 확인", Status: "active"}
-	if err := client.CheckConnection(); err != nil {
+	if err := client.CheckConnection(ctx); err != nil {
 		progressChan <- DeployProgress{Step: "connect", Message: "서버 연결 실패", Status: "error"}
 		return err
 	}
@@ -83,104 +155,222 @@ func (d *Deployer) DeployWithProgress(projectName string, output io.Writer, prog
 	// 백업 단계 제거 - GitHub이 백업 역할
 
 	progressChan <- DeployProgress{Step: "pull", Message: "코드 업데이트", Status: "active"}
+	if err := runHooks(ctx, client, proj, hookPrePull, proj.Hooks.PrePull, output); err != nil {
+		progressChan <- DeployProgress{Step: "pull", Message: "pre_pull 훅 실패", Status: "error"}
+		return err
+	}
 	fmt.Fprintf(output, "📥 Git pull 시작 (브랜치: %s)...\n", proj.Branch)
-	if err := client.GitPull(proj.Path, proj.Branch); err != nil {
+	pullCtx, cancel := stepContext(ctx, proj.Timeouts.Pull, defaultPullTimeout)
+	err := client.GitPull(pullCtx, proj.Path, proj.Branch)
+	cancel()
+	if err != nil {
 		progressChan <- DeployProgress{Step: "pull", Message: "코드 업데이트 실패", Status: "error"}
-		return fmt.Errorf("Git pull 실패: %v", err)
+		return fmt.Errorf("Git pull 실패: %w", err)
+	}
+	if err := runHooks(ctx, client, proj, hookPostPull, proj.Hooks.PostPull, output); err != nil {
+		progressChan <- DeployProgress{Step: "pull", Message: "post_pull 훅 실패", Status: "error"}
+		return err
 	}
 	progressChan <- DeployProgress{Step: "pull", Message: "코드 업데이트", Status: "completed"}
 
-	if hash, err := client.GetCurrentCommit(proj.Path); err == nil {
+	if hash, err := client.GetCurrentCommit(ctx, proj.Path); err == nil {
 		fmt.Fprintf(output, "📝 배포 커밋: %s\n", hash)
 	}
 
+	if len(proj.Files) > 0 || len(proj.Env) > 0 {
+		progressChan <- DeployProgress{Step: "files", Message: "설정 파일 업로드", Status: "active"}
+		if err := uploadFiles(ctx, client, proj, output); err != nil {
+			progressChan <- DeployProgress{Step: "files", Message: "설정 파일 업로드 실패", Status: "error"}
+			return err
+		}
+		if err := writeEnvFile(ctx, client, proj, output); err != nil {
+			progressChan <- DeployProgress{Step: "files", Message: "환경변수 파일 작성 실패", Status: "error"}
+			return err
+		}
+		progressChan <- DeployProgress{Step: "files", Message: "설정 파일 업로드", Status: "completed"}
+	}
+
 	progressChan <- DeployProgress{Step: "build", Message: "컨테이너 빌드 및 재시작", Status: "active"}
+	if err := runHooks(ctx, client, proj, hookPreUp, proj.Hooks.PreUp, output); err != nil {
+		progressChan <- DeployProgress{Step: "build", Message: "pre_up 훅 실패", Status: "error"}
+		return err
+	}
 	fmt.Fprintf(output, "🐳 Docker Compose 시작...\n")
-	if err := client.DockerComposeUpWithStreaming(proj.Path, proj.DockerCompose, output); err != nil {
+	buildCtx, cancel := stepContext(ctx, proj.Timeouts.Build, defaultBuildTimeout)
+	err = client.DockerComposeUpWithStreaming(buildCtx, proj.Path, proj.DockerCompose, output)
+	cancel()
+	if err != nil {
 		progressChan <- DeployProgress{Step: "build", Message: "컨테이너 빌드 실패", Status: "error"}
-		return fmt.Errorf("Docker Compose 실행 실패: %v", err)
+		return fmt.Errorf("Docker Compose 실행 실패: %w", err)
 	}
 	progressChan <- DeployProgress{Step: "build", Message: "컨테이너 빌드 및 재시작", Status: "completed"}
 
 	if proj.HealthCheck != "" {
 		progressChan <- DeployProgress{Step: "health", Message: "서비스 헬스체크", Status: "active"}
-		time.Sleep(5 * time.Second)
-
 		fmt.Fprintf(output, "🔍 헬스체크 시작: %s\n", proj.HealthCheck)
+
+		healthCtx, cancel := stepContext(ctx, proj.Timeouts.Health, defaultHealthTimeout)
+		status, err := waitHealthy(healthCtx, client, proj.HealthCheck)
+		cancel()
+		if err != nil {
+			progressChan <- DeployProgress{Step: "health", Message: "서비스 헬스체크 실패", Status: "error"}
+			return err
+		}
+
+		fmt.Fprintf(output, "💚 헬스체크 통과: HTTP %d\n", status)
 		progressChan <- DeployProgress{Step: "health", Message: "서비스 헬스체크", Status: "completed"}
 	}
 
+	// 헬스체크를 통과한 뒤 실행 (캐시 워밍 등)
+	if len(proj.Hooks.PostUp) > 0 {
+		progressChan <- DeployProgress{Step: "post_up", Message: "배포 후 훅 실행", Status: "active"}
+		if err := runHooks(ctx, client, proj, hookPostUp, proj.Hooks.PostUp, output); err != nil {
+			progressChan <- DeployProgress{Step: "post_up", Message: "배포 후 훅 실패", Status: "error"}
+			return err
+		}
+		progressChan <- DeployProgress{Step: "post_up", Message: "배포 후 훅 실행", Status: "completed"}
+	}
+
 	// 배포 완료 신호
 	progressChan <- DeployProgress{Step: "complete", Message: "배포 완료", Status: "completed"}
 	fmt.Fprintf(output, "✅ 배포 완료!\n")
 	return nil
 }
 
-func (d *Deployer) GetStatus(projectName string) (string, error) {
+func (d *Deployer) GetStatus(ctx context.Context, projectName string) (string, error) {
 	proj, exists := d.config.GetProject(projectName)
 	if !exists {
 		return "", fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
-	if err != nil {
-		return "", fmt.Errorf("SSH 연결 실패: %v", err)
-	}
-	defer client.Close()
-
-	return client.CheckContainerStatus(proj.Path, proj.DockerCompose)
+	var status string
+	err := ssh.WithReconnect(d.connector, proj.Server, func(client ssh.RemoteExecutor) error {
+		var err error
+		status, err = client.CheckContainerStatus(ctx, proj.Path, proj.DockerCompose)
+		return err
+	})
+	return status, err
 }
 
-func (d *Deployer) GetLogs(projectName string, lines string) (string, error) {
+func (d *Deployer) GetLogs(ctx context.Context, projectName string, lines string) (string, error) {
 	proj, exists := d.config.GetProject(projectName)
 	if !exists {
 		return "", fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
-	if err != nil {
-		return "", fmt.Errorf("SSH 연결 실패: %v", err)
-	}
-	defer client.Close()
-
-	return client.DockerLogs(proj.Path, proj.DockerCompose, lines)
+	var logs string
+	err := ssh.WithReconnect(d.connector, proj.Server, func(client ssh.RemoteExecutor) error {
+		var err error
+		logs, err = client.DockerLogs(ctx, proj.Path, proj.DockerCompose, lines)
+		return err
+	})
+	return logs, err
 }
 
-func (d *Deployer) GetEnvironmentVariables(projectName string) (map[string]string, error) {
+func (d *Deployer) GetEnvironmentVariables(ctx context.Context, projectName string) (map[string]string, error) {
 	proj, exists := d.config.GetProject(projectName)
 	if !exists {
 		return nil, fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
-	if err != nil {
-		return nil, fmt.Errorf("SSH 연결 실패: %v", err)
-	}
-	defer client.Close()
-
-	return client.GetEnvironmentVariables(proj.Path, proj.DockerCompose)
+	var envVars map[string]string
+	err := ssh.WithReconnect(d.connector, proj.Server, func(client ssh.RemoteExecutor) error {
+		var err error
+		envVars, err = client.GetEnvironmentVariables(ctx, proj.Path, proj.DockerCompose)
+		return err
+	})
+	return envVars, err
 }
 
-func (d *Deployer) Rollback(projectName string) error {
+func (d *Deployer) Rollback(ctx context.Context, projectName string) error {
 	proj, exists := d.config.GetProject(projectName)
 	if !exists {
 		return fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
+	client, err := d.connector.Connect(proj.Server)
 	if err != nil {
 		return fmt.Errorf("SSH 연결 실패: %v", err)
 	}
 	defer client.Close()
 
-	command := fmt.Sprintf("cd %s && git checkout HEAD~1", proj.Path)
-	if _, err := client.ExecuteCommand(command); err != nil {
-		return fmt.Errorf("롤백 실패: %v", err)
+	if _, err := client.Execute(ctx, ssh.Cmd("git", "checkout", "HEAD~1").In(proj.Path)); err != nil {
+		return fmt.Errorf("롤백 실패: %w", err)
 	}
 
-	if err := client.DockerComposeUp(proj.Path, proj.DockerCompose); err != nil {
-		return fmt.Errorf("컨테이너 재시작 실패: %v", err)
+	buildCtx, cancel := stepContext(ctx, proj.Timeouts.Build, defaultBuildTimeout)
+	defer cancel()
+	if err := client.DockerComposeUp(buildCtx, proj.Path, proj.DockerCompose); err != nil {
+		return fmt.Errorf("컨테이너 재시작 실패: %w", err)
 	}
 
 	return nil
 }
+
+// stepContext 단계별 제한 시간을 적용한 컨텍스트를 만듭니다
+func stepContext(ctx context.Context, timeout, fallback time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = fallback
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// uploadFiles 프로젝트에 지정된 로컬 파일을 서버로 올립니다
+func uploadFiles(ctx context.Context, client ssh.RemoteExecutor, proj config.Project, output io.Writer) error {
+	for _, file := range proj.Files {
+		mode, err := file.FileMode()
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(file.Source)
+		if err != nil {
+			return fmt.Errorf("업로드할 파일을 열 수 없습니다: %v", err)
+		}
+
+		dest := file.RemotePath(proj.Path)
+		fmt.Fprintf(output, "📤 파일 업로드: %s → %s\n", file.Source, dest)
+		err = client.Upload(ctx, dest, f, ssh.WriteOptions{Mode: mode, UID: file.UID, GID: file.GID})
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("파일 업로드 실패: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeEnvFile env 를 env_file 로 써서 compose 가 앱 컨테이너에 전달할 수 있게 합니다. 값은 출력에 남기지 않습니다
+func writeEnvFile(ctx context.Context, client ssh.RemoteExecutor, proj config.Project, output io.Writer) error {
+	if len(proj.Env) == 0 {
+		return nil
+	}
+	dest := proj.EnvFilePath()
+	fmt.Fprintf(output, "🔐 환경변수 파일 작성: %s (%d개)\n", dest, len(proj.Env))
+	if err := client.Upload(ctx, dest, strings.NewReader(renderEnv(proj.Env)), ssh.WriteOptions{Mode: 0600}); err != nil {
+		return fmt.Errorf("환경변수 파일 작성 실패: %w", err)
+	}
+	return nil
+}
+
+// renderEnv 이름 순으로 KEY=value 줄을 만듭니다
+func renderEnv(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, quoteEnv(env[k]))
+	}
+	return b.String()
+}
+
+// quoteEnv compose 가 $ 등을 치환하지 않도록 작은따옴표로 감쌉니다. 작은따옴표가 들어있으면 큰따옴표로 감싸고 이스케이프합니다
+func quoteEnv(value string) string {
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`).Replace(value) + `"`
+}
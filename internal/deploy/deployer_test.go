@@ -0,0 +1,309 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func newTestDeployer(t *testing.T, srv *sshtest.Server, proj config.Project) *Deployer {
+	t.Helper()
+
+	proj.Server = srv.ConnectionConfig()
+	if proj.Path == "" {
+		proj.Path = "/srv/app"
+	}
+	if proj.Branch == "" {
+		proj.Branch = "main"
+	}
+	if proj.DockerCompose == "" {
+		proj.DockerCompose = "docker-compose.prod.yml"
+	}
+
+	cfg := &config.Config{Projects: map[string]config.Project{"app": proj}}
+	return NewDeployer(cfg, ssh.DirectConnector)
+}
+
+func TestDeploy(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	d := newTestDeployer(t, srv, config.Project{})
+
+	if err := d.Deploy(context.Background(), "app"); err != nil {
+		t.Fatalf("Deploy() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"git pull origin main",
+		"docker compose -f docker-compose.prod.yml up -d --build",
+	} {
+		if !srv.Ran(want) {
+			t.Errorf("명령이 실행되지 않음: %q\n실행된 명령: %q", want, srv.Commands())
+		}
+	}
+}
+
+func TestDeployGitPullFallback(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("git pull", sshtest.Response{Stderr: "fatal: refusing to merge", ExitStatus: 1})
+	d := newTestDeployer(t, srv, config.Project{Branch: "release"})
+
+	if err := d.Deploy(context.Background(), "app"); err != nil {
+		t.Fatalf("Deploy() error = %v", err)
+	}
+	if !srv.Ran("git reset --hard origin/release") {
+		t.Errorf("pull 실패 후 reset 이 실행되지 않음: %q", srv.Commands())
+	}
+}
+
+func TestDeployComposeFailure(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("up -d --build", sshtest.Response{Stderr: "build failed", ExitStatus: 1})
+	d := newTestDeployer(t, srv, config.Project{})
+
+	err := d.Deploy(context.Background(), "app")
+	if err == nil || !strings.Contains(err.Error(), "build failed") {
+		t.Fatalf("Deploy() error = %v, want compose failure", err)
+	}
+}
+
+func TestDeployWithProgress(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("git log -1", sshtest.Response{Stdout: "abc1234|fix: something"})
+	srv.Handle("up -d --build", sshtest.Response{Stdout: "Container app-web-1  Started\n"})
+	d := newTestDeployer(t, srv, config.Project{})
+
+	var output bytes.Buffer
+	progress := make(chan DeployProgress, 32)
+	if err := d.DeployWithProgress(context.Background(), "app", &output, progress); err != nil {
+		t.Fatalf("DeployWithProgress() error = %v", err)
+	}
+	close(progress)
+
+	var steps []string
+	for p := range progress {
+		if p.Status == "completed" {
+			steps = append(steps, p.Step)
+		}
+	}
+	if got, want := strings.Join(steps, ","), "connect,pull,build,complete"; got != want {
+		t.Errorf("완료된 단계 = %s, want %s", got, want)
+	}
+
+	for _, want := range []string{"abc1234|fix: something", "Container app-web-1  Started"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("출력에 %q 가 없음:\n%s", want, output.String())
+		}
+	}
+}
+
+func TestDeployHealthThroughTunnel(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+
+	srv := sshtest.NewServer(t)
+	// 127.0.0.1 URL 은 sship 호스트가 아닌 SSH 연결을 통해 요청되어야 함
+	d := newTestDeployer(t, srv, config.Project{HealthCheck: backend.URL + "/health"})
+
+	var output bytes.Buffer
+	progress := make(chan DeployProgress, 32)
+	if err := d.DeployWithProgress(context.Background(), "app", &output, progress); err != nil {
+		t.Fatalf("DeployWithProgress() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "HTTP 204") {
+		t.Errorf("헬스체크 결과가 출력에 없음:\n%s", output.String())
+	}
+}
+
+func TestDeployEnvFile(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	dir := t.TempDir()
+	d := newTestDeployer(t, srv, config.Project{
+		Path: dir,
+		Env: map[string]string{
+			"DATABASE_URL": "postgres://app:s3cret@db/app",
+			"GREETING":     "it's $HOME",
+		},
+	})
+
+	var output bytes.Buffer
+	if err := d.DeployTo(context.Background(), "app", &output); err != nil {
+		t.Fatalf("DeployTo() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "DATABASE_URL='postgres://app:s3cret@db/app'\nGREETING=\"it's \\$HOME\"\n"; string(data) != want {
+		t.Errorf(".env = %q, want %q", data, want)
+	}
+	if fi, _ := os.Stat(filepath.Join(dir, ".env")); fi.Mode().Perm() != 0600 {
+		t.Errorf("권한 = %v, want 0600", fi.Mode().Perm())
+	}
+	if strings.Contains(output.String(), "s3cret") {
+		t.Errorf("환경변수 값이 출력에 노출됨:\n%s", output.String())
+	}
+}
+
+func TestDeployTimeout(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("up -d --build", sshtest.Response{Delay: 10 * time.Second})
+	d := newTestDeployer(t, srv, config.Project{
+		Timeouts: config.StepTimeouts{Build: 200 * time.Millisecond},
+	})
+
+	start := time.Now()
+	err := d.Deploy(context.Background(), "app")
+	if err == nil || !strings.Contains(err.Error(), "시간 초과") {
+		t.Fatalf("Deploy() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("제한 시간 후에도 %v 동안 대기함", elapsed)
+	}
+}
+
+func TestDeployQueueCancel(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	started := make(chan struct{}, 1)
+	srv.HandleFunc("up -d --build", func(string) sshtest.Response {
+		started <- struct{}{}
+		return sshtest.Response{Delay: 10 * time.Second}
+	})
+	q := NewDeployQueue(newTestDeployer(t, srv, config.Project{}))
+
+	events := q.Subscribe("test")
+	defer q.Unsubscribe("test")
+
+	job, err := q.Enqueue("app", "main")
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("docker compose 가 시작되지 않음")
+	}
+	if err := q.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Status == JobStatusCancelled {
+				got, _ := q.GetJob(job.ID)
+				if got.Status != JobStatusCancelled {
+					t.Errorf("job status = %s, want %s", got.Status, JobStatusCancelled)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("취소 이벤트를 받지 못함")
+		}
+	}
+}
+
+func TestDeployQueueCancelBeforeStart(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	q := NewDeployQueue(newTestDeployer(t, srv, config.Project{}))
+
+	// 워커가 작업을 꺼냈지만 아직 배포를 시작하지 않은 시점에 취소
+	testHookJobStarted = func(jobID string) {
+		if err := q.Cancel(jobID); err != nil {
+			t.Errorf("Cancel() error = %v", err)
+		}
+	}
+	defer func() { testHookJobStarted = nil }()
+
+	events := q.Subscribe("test")
+	defer q.Unsubscribe("test")
+
+	job, err := q.Enqueue("app", "main")
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Status != JobStatusCancelled {
+				continue
+			}
+			got, _ := q.GetJob(job.ID)
+			if got.Status != JobStatusCancelled {
+				t.Errorf("job status = %s, want %s", got.Status, JobStatusCancelled)
+			}
+			if cmds := srv.Commands(); len(cmds) != 0 {
+				t.Errorf("취소된 작업이 명령을 실행함: %v", cmds)
+			}
+			if history := q.GetHistory("app", 100); len(history) != 1 {
+				t.Errorf("history = %d jobs, want 1", len(history))
+			}
+			return
+		case <-timeout:
+			t.Fatal("취소 이벤트를 받지 못함")
+		}
+	}
+}
+
+func TestDeployQueueConnectionLost(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.HandleFunc("up -d --build", func(string) sshtest.Response {
+		// 빌드 중 NAT 가 유휴 연결을 끊은 상황
+		srv.StallKeepAlive(true)
+		return sshtest.Response{Delay: 10 * time.Second}
+	})
+
+	server := srv.ConnectionConfig()
+	server.KeepAlive = 100 * time.Millisecond
+	server.KeepAliveMaxMissed = 2
+	cfg := &config.Config{Projects: map[string]config.Project{"app": {
+		Server:        server,
+		Path:          "/srv/app",
+		Branch:        "main",
+		DockerCompose: "docker-compose.prod.yml",
+	}}}
+	q := NewDeployQueue(NewDeployer(cfg, ssh.DirectConnector))
+
+	events := q.Subscribe("test")
+	defer q.Unsubscribe("test")
+
+	job, err := q.Enqueue("app", "main")
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			switch ev.Status {
+			case JobStatusConnectionLost:
+				got, _ := q.GetJob(job.ID)
+				if got.Status != JobStatusConnectionLost {
+					t.Errorf("job status = %s, want %s", got.Status, JobStatusConnectionLost)
+				}
+				return
+			case JobStatusFailed, JobStatusCompleted:
+				t.Fatalf("이벤트 상태 = %s, want %s (%s)", ev.Status, JobStatusConnectionLost, ev.Message)
+			}
+		case <-timeout:
+			t.Fatal("연결 끊김 이벤트를 받지 못함")
+		}
+	}
+}
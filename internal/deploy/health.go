@@ -0,0 +1,69 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+)
+
+const healthPollInterval = 2 * time.Second
+
+// CheckHealth 헬스체크 URL 에 요청을 보내 상태 코드를 반환합니다.
+// localhost/127.0.0.1 을 가리키는 URL 은 sship 호스트가 아니라 SSH 연결을 통해 서버에서 요청합니다.
+func CheckHealth(ctx context.Context, client ssh.RemoteExecutor, healthURL string) (int, error) {
+	if isLoopbackURL(healthURL) {
+		return client.ProbeHTTP(ctx, healthURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("잘못된 헬스체크 URL 입니다: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// waitHealthy 헬스체크가 2xx/3xx 를 반환할 때까지 ctx 가 끝나기 전까지 반복합니다
+func waitHealthy(ctx context.Context, client ssh.RemoteExecutor, healthURL string) (int, error) {
+	var (
+		status  int
+		lastErr error
+	)
+	for {
+		status, lastErr = CheckHealth(ctx, client, healthURL)
+		if lastErr == nil && status >= 200 && status < 400 {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return status, fmt.Errorf("헬스체크 실패: %v", lastErr)
+			}
+			return status, fmt.Errorf("헬스체크 실패: HTTP %d", status)
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+func isLoopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,55 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+)
+
+// 훅에 timeout 이 지정되지 않은 경우의 제한 시간
+const defaultHookTimeout = 10 * time.Minute
+
+// 훅 단계 이름 (sship.yaml 의 hooks 키)
+const (
+	hookPrePull   = "pre_pull"
+	hookPostPull  = "post_pull"
+	hookPreUp     = "pre_up"
+	hookPostUp    = "post_up"
+	hookOnFailure = "on_failure"
+)
+
+// runHooks 단계의 훅을 프로젝트 경로에서 순서대로 실행하고 출력을 output 으로 보냅니다.
+// continue_on_error 가 아닌 훅이 실패하면 남은 훅을 실행하지 않고 에러를 반환합니다
+func runHooks(ctx context.Context, client ssh.RemoteExecutor, proj config.Project, stage string, hooks []config.Hook, output io.Writer) error {
+	for _, hook := range hooks {
+		fmt.Fprintf(output, "🪝 %s 훅 실행: %s\n", stage, hook.Run)
+
+		hookCtx, cancel := stepContext(ctx, hook.Timeout, defaultHookTimeout)
+		_, err := client.ExecuteWithStreaming(hookCtx, ssh.Cmd("sh", "-c", hook.Run).In(proj.Path), output)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if hook.ContinueOnError {
+			fmt.Fprintf(output, "⚠️ %s 훅 실패 (계속 진행): %v\n", stage, err)
+			continue
+		}
+		return fmt.Errorf("%s 훅 실패 (%s): %w", stage, hook.Run, err)
+	}
+	return nil
+}
+
+// runFailureHooks 배포가 실패했을 때 on_failure 훅을 실행합니다. 배포가 취소되었거나
+// 시간이 초과되어도 정리는 해야 하므로 ctx 의 취소는 따르지 않으며, 훅의 실패는 출력에만 남깁니다
+func runFailureHooks(ctx context.Context, client ssh.RemoteExecutor, proj config.Project, output io.Writer) {
+	hooks := make([]config.Hook, len(proj.Hooks.OnFailure))
+	for i, hook := range proj.Hooks.OnFailure {
+		hook.ContinueOnError = true
+		hooks[i] = hook
+	}
+	runHooks(context.WithoutCancel(ctx), client, proj, hookOnFailure, hooks, output)
+}
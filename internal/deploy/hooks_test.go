@@ -0,0 +1,128 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestDeployHooks(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("./migrate", sshtest.Response{Stdout: "migrated 3\n"})
+	srv.Handle("warmup", sshtest.Response{Stderr: "connection refused", ExitStatus: 7})
+	d := newTestDeployer(t, srv, config.Project{Hooks: config.Hooks{
+		PrePull: []config.Hook{{Run: "echo before-pull"}},
+		PreUp:   []config.Hook{{Run: "./migrate"}},
+		PostUp:  []config.Hook{{Run: "curl -fsS localhost/warmup", ContinueOnError: true}},
+	}})
+
+	var output bytes.Buffer
+	progress := make(chan DeployProgress, 32)
+	if err := d.DeployWithProgress(context.Background(), "app", &output, progress); err != nil {
+		t.Fatalf("DeployWithProgress() error = %v", err)
+	}
+
+	// 훅은 프로젝트 경로에서 단계 순서대로 실행
+	var order []string
+	for _, cmd := range srv.Commands() {
+		for _, want := range []string{"before-pull", "git pull", "./migrate", "up -d --build", "warmup"} {
+			if strings.Contains(cmd, want) {
+				order = append(order, want)
+				if strings.Contains(cmd, "sh -c") && !strings.HasPrefix(cmd, "cd -- /srv/app && ") {
+					t.Errorf("훅이 프로젝트 경로에서 실행되지 않음: %q", cmd)
+				}
+			}
+		}
+	}
+	if got, want := strings.Join(order, ","), "before-pull,git pull,./migrate,up -d --build,warmup"; got != want {
+		t.Errorf("실행 순서 = %s, want %s", got, want)
+	}
+
+	for _, want := range []string{"migrated 3", "connection refused", "post_up 훅 실패 (계속 진행)", "✅ 배포 완료"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("출력에 %q 가 없음:\n%s", want, output.String())
+		}
+	}
+}
+
+func TestDeployHookFailure(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("./migrate", sshtest.Response{Stderr: "migration 42 failed\n", ExitStatus: 1})
+	srv.Handle("cleanup", sshtest.Response{Stdout: "cleaned\n"})
+	d := newTestDeployer(t, srv, config.Project{Hooks: config.Hooks{
+		PreUp:     []config.Hook{{Run: "./migrate"}, {Run: "echo never"}},
+		OnFailure: []config.Hook{{Run: "./cleanup"}},
+	}})
+
+	q := NewDeployQueue(d)
+	events := q.Subscribe("test")
+	defer q.Unsubscribe("test")
+	job, err := q.Enqueue("app", "main")
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	timeout := time.After(5 * time.Second)
+wait:
+	for {
+		select {
+		case ev := <-events:
+			switch ev.Status {
+			case JobStatusFailed:
+				break wait
+			case JobStatusCompleted:
+				t.Fatal("훅이 실패했는데 배포가 완료됨")
+			}
+		case <-timeout:
+			t.Fatal("실패 이벤트를 받지 못함")
+		}
+	}
+
+	if srv.Ran("up -d --build") || srv.Ran("echo never") {
+		t.Errorf("훅 실패 후에도 배포가 계속됨: %q", srv.Commands())
+	}
+	if !srv.Ran("./cleanup") {
+		t.Errorf("on_failure 훅이 실행되지 않음: %q", srv.Commands())
+	}
+
+	got, _ := q.GetJob(job.ID)
+	if !strings.Contains(got.Error, "pre_up 훅 실패") {
+		t.Errorf("job error = %q", got.Error)
+	}
+	output := strings.Join(got.Output, "\n")
+	for _, want := range []string{"migration 42 failed", "🪝 on_failure 훅 실행: ./cleanup", "cleaned"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("작업 출력에 %q 가 없음:\n%s", want, output)
+		}
+	}
+}
+
+func TestDeployPostUpAfterHealth(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer backend.Close()
+
+	srv := sshtest.NewServer(t)
+	d := newTestDeployer(t, srv, config.Project{
+		HealthCheck: backend.URL + "/health",
+		Timeouts:    config.StepTimeouts{Health: 200 * time.Millisecond},
+		Hooks:       config.Hooks{PostUp: []config.Hook{{Run: "curl -fsS localhost/warmup"}}},
+	})
+
+	// 대기열이 쓰는 경로도 헬스체크를 통과해야 post_up 을 실행
+	var output bytes.Buffer
+	err := d.DeployTo(context.Background(), "app", &output)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 503") {
+		t.Fatalf("DeployTo() error = %v, want health check failure", err)
+	}
+	if srv.Ran("warmup") {
+		t.Errorf("헬스체크 실패 후 post_up 이 실행됨: %q", srv.Commands())
+	}
+}
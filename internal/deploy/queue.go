@@ -1,9 +1,14 @@
 package deploy
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
 )
 
 type JobStatus string
@@ -13,6 +18,9 @@ const (
 	JobStatusRunning   JobStatus = "running"
 	JobStatusCompleted JobStatus = "completed"
 	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+	// JobStatusConnectionLost 배포 중 SSH 연결이 끊어져 실패한 경우
+	JobStatusConnectionLost JobStatus = "connection_lost"
 )
 
 type DeployJob struct {
@@ -35,9 +43,17 @@ type DeployQueue struct {
 	deployer  *Deployer
 	listeners map[string][]chan DeployEvent
 	listenerMu sync.RWMutex
+	cancels   map[string]context.CancelFunc
 }
 
+// 배포 작업과 관계없는 이벤트 종류 (DeployEvent.Event)
+const (
+	EventConfigChanged = "config.changed"
+	EventConfigInvalid = "config.invalid"
+)
+
 type DeployEvent struct {
+	Event   string    `json:"event,omitempty"`
 	JobID   string    `json:"job_id"`
 	Service string    `json:"service"`
 	Status  JobStatus `json:"status"`
@@ -52,6 +68,7 @@ func NewDeployQueue(deployer *Deployer) *DeployQueue {
 		history:   make([]*DeployJob, 0),
 		deployer:  deployer,
 		listeners: make(map[string][]chan DeployEvent),
+		cancels:   make(map[string]context.CancelFunc),
 	}
 	
 	// Worker goroutine
@@ -91,23 +108,60 @@ func (q *DeployQueue) Enqueue(serviceName string, branch string) (*DeployJob, er
 	return job, nil
 }
 
+// testHookJobStarted 작업을 꺼낸 직후 테스트에서 끼어들 수 있게 하는 훅
+var testHookJobStarted func(jobID string)
+
 func (q *DeployQueue) worker() {
 	for jobID := range q.queue {
-		q.mu.RLock()
+		q.mu.Lock()
 		job, exists := q.jobs[jobID]
-		q.mu.RUnlock()
-		
-		if !exists {
+		if !exists || job.Status == JobStatusCancelled {
+			q.mu.Unlock()
 			continue
 		}
+		// 취소 함수를 등록하는 잠금 안에서 실행 중으로 바꿔야
+		// 그 사이에 들어온 Cancel 이 대기 중 작업으로 처리하지 않음
+		ctx, cancel := context.WithCancel(context.Background())
+		q.cancels[jobID] = cancel
+		job.Status = JobStatusRunning
+		q.mu.Unlock()
 		
-		// 상태 업데이트
-		q.updateJobStatus(jobID, JobStatusRunning, "")
+		if testHookJobStarted != nil {
+			testHookJobStarted(jobID)
+		}
 		
 		// 배포 실행
-		err := q.deployer.Deploy(job.ServiceName)
+		output := &jobOutput{q: q, jobID: jobID}
+		err := q.deployer.DeployTo(ctx, job.ServiceName, output)
+		output.Flush()
 		
-		if err != nil {
+		// cancel 호출 전에 사용자가 취소했는지 확인
+		cancelled := errors.Is(ctx.Err(), context.Canceled)
+		q.mu.Lock()
+		delete(q.cancels, jobID)
+		q.mu.Unlock()
+		cancel()
+
+		switch {
+		case err != nil && cancelled:
+			q.updateJobStatus(jobID, JobStatusCancelled, err.Error())
+			q.publishEvent(DeployEvent{
+				JobID:   jobID,
+				Service: job.ServiceName,
+				Status:  JobStatusCancelled,
+				Message: "배포가 취소되었습니다",
+				Time:    time.Now(),
+			})
+		case errors.Is(err, ssh.ErrConnectionLost):
+			q.updateJobStatus(jobID, JobStatusConnectionLost, err.Error())
+			q.publishEvent(DeployEvent{
+				JobID:   jobID,
+				Service: job.ServiceName,
+				Status:  JobStatusConnectionLost,
+				Message: fmt.Sprintf("배포 중 서버와의 SSH 연결이 끊어졌습니다: %v", err),
+				Time:    time.Now(),
+			})
+		case err != nil:
 			q.updateJobStatus(jobID, JobStatusFailed, err.Error())
 			q.publishEvent(DeployEvent{
 				JobID:   jobID,
@@ -116,7 +170,7 @@ func (q *DeployQueue) worker() {
 				Message: fmt.Sprintf("배포 실패: %v", err),
 				Time:    time.Now(),
 			})
-		} else {
+		default:
 			q.updateJobStatus(jobID, JobStatusCompleted, "")
 			q.publishEvent(DeployEvent{
 				JobID:   jobID,
@@ -132,13 +186,86 @@ func (q *DeployQueue) worker() {
 	}
 }
 
+// jobOutput 배포 출력(훅, 파일 업로드)을 줄 단위로 작업의 Output 에 추가합니다
+type jobOutput struct {
+	q     *DeployQueue
+	jobID string
+	buf   []byte
+}
+
+func (w *jobOutput) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.appendLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush 줄바꿈으로 끝나지 않은 마지막 출력을 추가합니다
+func (w *jobOutput) Flush() {
+	if len(w.buf) > 0 {
+		w.appendLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *jobOutput) appendLine(line string) {
+	w.q.mu.Lock()
+	defer w.q.mu.Unlock()
+	if job, exists := w.q.jobs[w.jobID]; exists {
+		job.Output = append(job.Output, line)
+	}
+}
+
+// Cancel 대기 중인 작업은 건너뛰도록 표시하고, 실행 중인 작업은 원격 명령을 중단합니다
+func (q *DeployQueue) Cancel(jobID string) error {
+	q.mu.Lock()
+	job, exists := q.jobs[jobID]
+	if !exists {
+		q.mu.Unlock()
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+
+	switch job.Status {
+	case JobStatusPending:
+		job.Status = JobStatusCancelled
+		job.CompletedAt = time.Now()
+		q.mu.Unlock()
+
+		q.addToHistory(job)
+		q.publishEvent(DeployEvent{
+			JobID:   jobID,
+			Service: job.ServiceName,
+			Status:  JobStatusCancelled,
+			Message: "배포가 취소되었습니다",
+			Time:    time.Now(),
+		})
+		return nil
+	case JobStatusRunning:
+		cancel := q.cancels[jobID]
+		q.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+		return nil
+	default:
+		q.mu.Unlock()
+		return fmt.Errorf("이미 종료된 작업입니다: %s", job.Status)
+	}
+}
+
 func (q *DeployQueue) updateJobStatus(jobID string, status JobStatus, errorMsg string) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	
 	if job, exists := q.jobs[jobID]; exists {
 		job.Status = status
-		if status == JobStatusCompleted || status == JobStatusFailed {
+		if status == JobStatusCompleted || status == JobStatusFailed || status == JobStatusCancelled || status == JobStatusConnectionLost {
 			job.CompletedAt = time.Now()
 		}
 		if errorMsg != "" {
@@ -225,6 +352,15 @@ func (q *DeployQueue) Unsubscribe(clientID string) {
 	}
 }
 
+// Notify 설정 변경처럼 배포 작업과 관계없는 이벤트를 구독자에게 보냅니다
+func (q *DeployQueue) Notify(event string, message string) {
+	q.publishEvent(DeployEvent{
+		Event:   event,
+		Message: message,
+		Time:    time.Now(),
+	})
+}
+
 func (q *DeployQueue) publishEvent(event DeployEvent) {
 	q.listenerMu.RLock()
 	defer q.listenerMu.RUnlock()
@@ -238,4 +374,4 @@ func (q *DeployQueue) publishEvent(event DeployEvent) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
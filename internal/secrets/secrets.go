@@ -0,0 +1,267 @@
+// Package secrets SSH 비밀번호, 키, 앱 시크릿을 마스터 키로 암호화한 파일에 보관합니다.
+// 파일 전체를 AES-256-GCM 으로 암호화하며, 마스터 키에서 scrypt 로 암호화 키를 유도합니다.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// MasterKeyEnv 마스터 키를 담는 환경변수
+const MasterKeyEnv = "SSHIP_MASTER_KEY"
+
+const (
+	fileVersion = 1
+	// 암호문을 다른 파일 형식에 재사용하지 못하도록 묶는 추가 데이터
+	additionalData = "sship-secrets-v1"
+)
+
+// ErrNotFound 시크릿이 없는 경우
+var ErrNotFound = errors.New("시크릿을 찾을 수 없습니다")
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// Info 시크릿 메타데이터. 값은 API 로 돌려주지 않습니다
+type Info struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type secret struct {
+	Value     string    `json:"value"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// encryptedFile 디스크에 저장되는 형식 ([]byte 는 base64)
+type encryptedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// Store 암호화된 시크릿 파일
+type Store struct {
+	path string
+	aead cipher.AEAD
+	salt []byte
+
+	mu      sync.RWMutex
+	secrets map[string]secret
+}
+
+// LoadMasterKey SSHIP_MASTER_KEY 환경변수나 keyFile 에서 마스터 키를 읽습니다. 둘 다 없으면 빈 문자열
+func LoadMasterKey(keyFile string) (string, error) {
+	if key := os.Getenv(MasterKeyEnv); key != "" {
+		return key, nil
+	}
+	if keyFile == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return "", fmt.Errorf("마스터 키 파일을 읽을 수 없습니다: %v", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("마스터 키 파일이 비어 있습니다: %s", keyFile)
+	}
+	return key, nil
+}
+
+// Open path 의 시크릿 파일을 masterKey 로 엽니다. 파일이 없으면 빈 저장소로 시작하고 첫 저장 시 만듭니다.
+func Open(path string, masterKey string) (*Store, error) {
+	if masterKey == "" {
+		return nil, fmt.Errorf("마스터 키가 설정되지 않았습니다 (%s)", MasterKeyEnv)
+	}
+
+	s := &Store{path: path, secrets: make(map[string]secret)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.salt = make([]byte, 16)
+		if _, err := io.ReadFull(rand.Reader, s.salt); err != nil {
+			return nil, fmt.Errorf("솔트 생성 실패: %v", err)
+		}
+		if s.aead, err = newAEAD(masterKey, s.salt); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("시크릿 파일을 읽을 수 없습니다: %v", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("시크릿 파일 형식 오류: %v", err)
+	}
+	if file.Version != fileVersion || file.KDF != "scrypt" {
+		return nil, fmt.Errorf("지원하지 않는 시크릿 파일 형식입니다 (version %d, kdf %s)", file.Version, file.KDF)
+	}
+
+	s.salt = file.Salt
+	if s.aead, err = newAEAD(masterKey, s.salt); err != nil {
+		return nil, err
+	}
+	plain, err := s.aead.Open(nil, file.Nonce, file.Data, []byte(additionalData))
+	if err != nil {
+		return nil, fmt.Errorf("시크릿 파일을 복호화할 수 없습니다 (마스터 키를 확인하세요)")
+	}
+	if err := json.Unmarshal(plain, &s.secrets); err != nil {
+		return nil, fmt.Errorf("시크릿 파일 형식 오류: %v", err)
+	}
+	return s, nil
+}
+
+func newAEAD(masterKey string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(masterKey), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("암호화 키 유도 실패: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ValidateName 시크릿 이름은 영문, 숫자, '_', '.', '-' 로 128자 이하
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("잘못된 시크릿 이름입니다 (영문, 숫자, _ . - 만 사용): %q", name)
+	}
+	return nil
+}
+
+// Get 시크릿 값. config 의 secret:NAME 참조 해석에 사용됩니다
+func (s *Store) Get(name string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sec, ok := s.secrets[name]
+	return sec.Value, ok
+}
+
+// List 시크릿 메타데이터를 이름순으로 반환합니다
+func (s *Store) List() []Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]Info, 0, len(s.secrets))
+	for name, sec := range s.secrets {
+		infos = append(infos, Info{
+			Name:      name,
+			Version:   sec.Version,
+			CreatedAt: sec.CreatedAt,
+			UpdatedAt: sec.UpdatedAt,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Set 시크릿을 만들거나 새 값으로 교체(rotate)하고 파일에 저장합니다
+func (s *Store) Set(name, value string) (Info, error) {
+	if err := ValidateName(name); err != nil {
+		return Info{}, err
+	}
+	if value == "" {
+		return Info{}, fmt.Errorf("시크릿 값이 비어 있습니다")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	prev, exists := s.secrets[name]
+	sec := secret{Value: value, Version: prev.Version + 1, CreatedAt: prev.CreatedAt, UpdatedAt: now}
+	if !exists {
+		sec.CreatedAt = now
+	}
+	s.secrets[name] = sec
+
+	if err := s.saveLocked(); err != nil {
+		if exists {
+			s.secrets[name] = prev
+		} else {
+			delete(s.secrets, name)
+		}
+		return Info{}, err
+	}
+	return Info{Name: name, Version: sec.Version, CreatedAt: sec.CreatedAt, UpdatedAt: sec.UpdatedAt}, nil
+}
+
+// Delete 시크릿을 삭제하고 파일에 저장합니다
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.secrets[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.secrets, name)
+	if err := s.saveLocked(); err != nil {
+		s.secrets[name] = prev
+		return err
+	}
+	return nil
+}
+
+// saveLocked 임시 파일에 쓴 뒤 rename 하여 중간에 실패해도 기존 파일이 남도록 합니다
+func (s *Store) saveLocked() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return fmt.Errorf("시크릿 직렬화 실패: %v", err)
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("nonce 생성 실패: %v", err)
+	}
+	data, err := json.MarshalIndent(encryptedFile{
+		Version: fileVersion,
+		KDF:     "scrypt",
+		Salt:    s.salt,
+		Nonce:   nonce,
+		Data:    s.aead.Seal(nil, nonce, plain, []byte(additionalData)),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("시크릿 직렬화 실패: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".sship_secrets-*")
+	if err != nil {
+		return fmt.Errorf("시크릿 파일 저장 실패: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("시크릿 파일 저장 실패: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("시크릿 파일 저장 실패: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("시크릿 파일 저장 실패: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sship_secrets.enc")
+
+	store, err := Open(path, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := store.Set("db-password", "hunter2"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	info, err := store.Set("db-password", "hunter3")
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if info.Version != 2 {
+		t.Errorf("교체 후 version = %d, want 2", info.Version)
+	}
+	if _, err := store.Set("api key", "x"); err == nil {
+		t.Error("잘못된 이름이 허용됨")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("hunter")) {
+		t.Error("시크릿이 평문으로 저장됨")
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0600 {
+		t.Errorf("파일 권한 = %v, want 0600", fi.Mode().Perm())
+	}
+
+	reopened, err := Open(path, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if value, ok := reopened.Get("db-password"); !ok || value != "hunter3" {
+		t.Errorf("Get() = %q, %v", value, ok)
+	}
+	if list := reopened.List(); len(list) != 1 || list[0].Name != "db-password" {
+		t.Errorf("List() = %+v", list)
+	}
+
+	if _, err := Open(path, "wrong key"); err == nil {
+		t.Error("다른 마스터 키로 열림")
+	}
+
+	if err := reopened.Delete("db-password"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := reopened.Delete("db-password"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() error = %v, want ErrNotFound", err)
+	}
+}
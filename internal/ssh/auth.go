@@ -0,0 +1,126 @@
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// 인증 방식 이름 (auth_order 에 사용)
+const (
+	AuthAgent    = "agent"
+	AuthKey      = "key"
+	AuthPassword = "password"
+)
+
+var defaultAuthOrder = []string{AuthAgent, AuthKey, AuthPassword}
+
+// authMethods 설정된 인증 수단을 auth_order 순서대로 구성합니다.
+// ssh-agent 연결이 열린 경우 함께 반환되는 conn 은 호출자가 닫아야 합니다.
+func (c ConnectionConfig) authMethods() ([]ssh.AuthMethod, net.Conn, error) {
+	order := c.AuthOrder
+	explicit := len(order) > 0
+	if !explicit {
+		order = defaultAuthOrder
+	}
+
+	var (
+		methods   []ssh.AuthMethod
+		agentConn net.Conn
+	)
+
+	for _, name := range order {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case AuthAgent:
+			socket := c.AgentSocket
+			if socket == "" && explicit {
+				socket = os.Getenv("SSH_AUTH_SOCK")
+			}
+			if socket == "" || agentConn != nil {
+				continue
+			}
+			conn, err := net.Dial("unix", expandHome(socket))
+			if err != nil {
+				return nil, nil, fmt.Errorf("ssh-agent 연결 실패: %v", err)
+			}
+			agentConn = conn
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+
+		case AuthKey:
+			if c.KeyFile == "" && c.PrivateKey == "" {
+				continue
+			}
+			signer, err := c.keySigner()
+			if err != nil {
+				if agentConn != nil {
+					agentConn.Close()
+				}
+				return nil, nil, err
+			}
+			methods = append(methods, ssh.PublicKeys(signer))
+
+		case AuthPassword:
+			if c.Password == "" {
+				continue
+			}
+			methods = append(methods, ssh.Password(c.Password))
+
+		default:
+			if agentConn != nil {
+				agentConn.Close()
+			}
+			return nil, nil, fmt.Errorf("알 수 없는 인증 방식입니다: %s", name)
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, nil, fmt.Errorf("사용 가능한 인증 수단이 없습니다 (password, key_file, private_key, agent_socket 중 하나가 필요합니다)")
+	}
+
+	return methods, agentConn, nil
+}
+
+// keySigner 인라인 PEM 또는 키 파일에서 서명자를 생성합니다 (사용자 인증서가 있으면 함께 제시)
+func (c ConnectionConfig) keySigner() (ssh.Signer, error) {
+	pemBytes := []byte(c.PrivateKey)
+	source := "private_key"
+	if len(pemBytes) == 0 {
+		data, err := os.ReadFile(expandHome(c.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("개인키 파일을 읽을 수 없습니다: %v", err)
+		}
+		pemBytes = data
+		source = c.KeyFile
+	}
+
+	var (
+		signer ssh.Signer
+		err    error
+	)
+	if c.KeyPassphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(pemBytes, []byte(c.KeyPassphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(pemBytes)
+	}
+	if err != nil {
+		if _, ok := err.(*ssh.PassphraseMissingError); ok {
+			return nil, fmt.Errorf("암호화된 개인키입니다. key_passphrase 가 필요합니다 (%s)", source)
+		}
+		return nil, fmt.Errorf("개인키 파싱 실패 (%s): %v", source, err)
+	}
+	return c.certSigner(signer)
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
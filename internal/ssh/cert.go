@@ -0,0 +1,137 @@
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// certificate 설정된 사용자 인증서를 읽습니다. 인증서가 없으면 nil 을 반환합니다.
+// certificate/cert_file 이 비어 있으면 OpenSSH 와 같이 key_file 옆의 "<key_file>-cert.pub" 를 사용합니다.
+func (c ConnectionConfig) certificate() (*ssh.Certificate, error) {
+	data := []byte(c.Certificate)
+	source := "certificate"
+	if len(data) == 0 {
+		path := c.CertFile
+		if path == "" {
+			if c.KeyFile == "" {
+				return nil, nil
+			}
+			path = c.KeyFile + "-cert.pub"
+			if _, err := os.Stat(expandHome(path)); err != nil {
+				return nil, nil
+			}
+		}
+		var err error
+		if data, err = os.ReadFile(expandHome(path)); err != nil {
+			return nil, fmt.Errorf("인증서 파일을 읽을 수 없습니다: %v", err)
+		}
+		source = path
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("인증서 파싱 실패 (%s): %v", source, err)
+	}
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("SSH 인증서가 아닙니다 (%s): %s", source, key.Type())
+	}
+	if cert.CertType != ssh.UserCert {
+		return nil, fmt.Errorf("사용자 인증서가 아닙니다 (%s)", source)
+	}
+
+	now := uint64(time.Now().Unix())
+	if now < cert.ValidAfter {
+		return nil, fmt.Errorf("인증서가 아직 유효하지 않습니다 (%s, 시작: %s)",
+			source, time.Unix(int64(cert.ValidAfter), 0).Format(time.RFC3339))
+	}
+	if cert.ValidBefore != ssh.CertTimeInfinity && now >= cert.ValidBefore {
+		return nil, fmt.Errorf("인증서가 만료되었습니다 (%s, 만료: %s)",
+			source, time.Unix(int64(cert.ValidBefore), 0).Format(time.RFC3339))
+	}
+	return cert, nil
+}
+
+// certSigner signer 에 사용자 인증서가 설정되어 있으면 인증서 서명자로 감쌉니다
+func (c ConnectionConfig) certSigner(signer ssh.Signer) (ssh.Signer, error) {
+	cert, err := c.certificate()
+	if err != nil || cert == nil {
+		return signer, err
+	}
+	certSigner, err := ssh.NewCertSigner(cert, signer)
+	if err != nil {
+		return nil, fmt.Errorf("인증서와 개인키가 일치하지 않습니다: %v", err)
+	}
+	return certSigner, nil
+}
+
+// hostCACallback 서버가 host_ca 로 서명된 호스트 인증서를 제시하는지 확인합니다.
+// 호스트별 지문(known_hosts) 대신 사용하며, 인증서가 아닌 일반 호스트 키는 거부합니다.
+func (c ConnectionConfig) hostCACallback(seen *ssh.PublicKey) (ssh.HostKeyCallback, error) {
+	cas, err := parsePublicKeys(c.HostCA)
+	if err != nil {
+		return nil, fmt.Errorf("host_ca 설정 오류: %v", err)
+	}
+
+	checker := &ssh.CertChecker{
+		IsHostAuthority: func(auth ssh.PublicKey, _ string) bool {
+			for _, ca := range cas {
+				if bytes.Equal(ca.Marshal(), auth.Marshal()) {
+					return true
+				}
+			}
+			return false
+		},
+		HostKeyFallback: func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+			return fmt.Errorf("호스트 인증서가 아닌 키를 받았습니다 (%s, %s)", hostname, ssh.FingerprintSHA256(key))
+		},
+	}
+
+	port := c.Port
+	if port == 0 {
+		port = 22
+	}
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(port))
+	return func(_ string, remote net.Addr, key ssh.PublicKey) error {
+		*seen = key
+		// 주소는 설정된 호스트 이름으로 검사 (점프 호스트 경유 시에도 동일).
+		// CheckHostKey 는 CA 서명, principal 과 유효 기간의 시작과 끝을 모두 확인합니다
+		if err := checker.CheckHostKey(addr, remote, key); err != nil {
+			return fmt.Errorf("호스트 인증서 검증 실패: %v", err)
+		}
+		return nil
+	}, nil
+}
+
+// parsePublicKeys authorized_keys 형식의 인라인 공개키 또는 공개키 파일 경로를 읽습니다
+func parsePublicKeys(value string) ([]ssh.PublicKey, error) {
+	data := []byte(value)
+	if !strings.Contains(value, " ") {
+		file, err := os.ReadFile(expandHome(value))
+		if err != nil {
+			return nil, fmt.Errorf("CA 공개키 파일을 읽을 수 없습니다: %v", err)
+		}
+		data = file
+	}
+
+	var keys []ssh.PublicKey
+	for len(bytes.TrimSpace(data)) > 0 {
+		key, _, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("CA 공개키 파싱 실패: %v", err)
+		}
+		keys = append(keys, key)
+		data = rest
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("CA 공개키가 없습니다")
+	}
+	return keys, nil
+}
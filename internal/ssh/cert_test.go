@@ -0,0 +1,138 @@
+package ssh_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+	xssh "golang.org/x/crypto/ssh"
+)
+
+func newSigner(t *testing.T) (xssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := xssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, priv
+}
+
+func signUserCert(t *testing.T, ca xssh.Signer, key xssh.PublicKey, validBefore time.Time) string {
+	t.Helper()
+	cert := &xssh.Certificate{
+		Key:             key,
+		CertType:        xssh.UserCert,
+		KeyId:           "deploy@ci",
+		ValidPrincipals: []string{sshtest.User},
+		ValidAfter:      uint64(time.Now().Add(-time.Minute).Unix()),
+		ValidBefore:     uint64(validBefore.Unix()),
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	return string(xssh.MarshalAuthorizedKey(cert))
+}
+
+func TestCertificateAuth(t *testing.T) {
+	ca, _ := newSigner(t)
+	user, userPriv := newSigner(t)
+	block, err := xssh.MarshalPrivateKey(userPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := sshtest.NewServer(t)
+	srv.TrustUserCA(ca.PublicKey())
+	srv.UseHostCertificate(t, ca, "127.0.0.1")
+
+	base := srv.ConnectionConfig()
+	base.Password = ""
+	base.PrivateKey = string(pem.EncodeToMemory(block))
+	base.HostCA = string(xssh.MarshalAuthorizedKey(ca.PublicKey()))
+
+	t.Run("valid", func(t *testing.T) {
+		config := base
+		config.Certificate = signUserCert(t, ca, user.PublicKey(), time.Now().Add(time.Hour))
+
+		client, err := ssh.NewClient(config)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		client.Close()
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		config := base
+		config.Certificate = signUserCert(t, ca, user.PublicKey(), time.Now().Add(-time.Second))
+
+		_, err := ssh.NewClient(config)
+		if err == nil || !strings.Contains(err.Error(), "만료") {
+			t.Fatalf("NewClient() error = %v, want 만료 오류", err)
+		}
+	})
+
+	t.Run("not yet valid", func(t *testing.T) {
+		cert := &xssh.Certificate{
+			Key:             user.PublicKey(),
+			CertType:        xssh.UserCert,
+			ValidPrincipals: []string{sshtest.User},
+			ValidAfter:      uint64(time.Now().Add(time.Hour).Unix()),
+			ValidBefore:     xssh.CertTimeInfinity,
+		}
+		if err := cert.SignCert(rand.Reader, ca); err != nil {
+			t.Fatal(err)
+		}
+		config := base
+		config.Certificate = string(xssh.MarshalAuthorizedKey(cert))
+
+		_, err := ssh.NewClient(config)
+		if err == nil || !strings.Contains(err.Error(), "아직 유효하지 않습니다") {
+			t.Fatalf("NewClient() error = %v, want 유효 기간 오류", err)
+		}
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		other, _ := newSigner(t)
+		config := base
+		config.Certificate = signUserCert(t, ca, other.PublicKey(), time.Now().Add(time.Hour))
+
+		_, err := ssh.NewClient(config)
+		if err == nil || !strings.Contains(err.Error(), "일치하지 않습니다") {
+			t.Fatalf("NewClient() error = %v, want 키 불일치 오류", err)
+		}
+	})
+
+	t.Run("untrusted host CA", func(t *testing.T) {
+		otherCA, _ := newSigner(t)
+		config := base
+		config.Certificate = signUserCert(t, ca, user.PublicKey(), time.Now().Add(time.Hour))
+		config.HostCA = string(xssh.MarshalAuthorizedKey(otherCA.PublicKey()))
+
+		_, err := ssh.NewClient(config)
+		if err == nil || !strings.Contains(err.Error(), "호스트 인증서 검증 실패") {
+			t.Fatalf("NewClient() error = %v, want 호스트 인증서 검증 실패", err)
+		}
+	})
+}
+
+func TestHostCARejectsPlainHostKey(t *testing.T) {
+	ca, _ := newSigner(t)
+	srv := sshtest.NewServer(t)
+
+	config := srv.ConnectionConfig()
+	config.HostCA = string(xssh.MarshalAuthorizedKey(ca.PublicKey()))
+
+	_, err := ssh.NewClient(config)
+	if err == nil || !strings.Contains(err.Error(), "호스트 인증서가 아닌 키") {
+		t.Fatalf("NewClient() error = %v, want 일반 호스트 키 거부", err)
+	}
+}
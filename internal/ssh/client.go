@@ -1,306 +1,552 @@
 package ssh
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 )
 
 type Client struct {
-	client *ssh.Client
-	config *ssh.ClientConfig
-	host   string
-	port   int
+	client     *ssh.Client
+	config     *ssh.ClientConfig
+	jumps      []*ssh.Client
+	agentConns []net.Conn
+	host       string
+	port       int
+	sudo       SudoConfig
+
+	// 컨테이너 런타임: 설정값과 감지 결과 (풀에서 빌린 복사본과 공유)
+	runtimeName string
+	runtime     *runtimeCache
+
+	hostKey       ssh.PublicKey
+	hostKeyPinned bool
+
+	// keepalive 로 감시하는 연결 상태 (풀에서 빌린 복사본과 공유)
+	health *connHealth
+
+	// 풀에서 빌린 경우 Close 는 연결을 닫지 않고 반납
+	pool     *Pool
+	entry    *poolEntry
+	released *atomic.Bool
 }
 
 type ConnectionConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	User     string `json:"user"`
-	Password string `json:"-"`
+	Host     string `json:"host" yaml:"host"`
+	Port     int    `json:"port" yaml:"port"`
+	User     string `json:"user" yaml:"user"`
+	Password string `json:"-" yaml:"password"`
+
+	// 공개키 인증: 키 파일 경로 또는 인라인 PEM (passphrase 선택)
+	KeyFile       string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	PrivateKey    string `json:"-" yaml:"private_key,omitempty"`
+	KeyPassphrase string `json:"-" yaml:"key_passphrase,omitempty"`
+
+	// CA 가 서명한 사용자 인증서: 파일 경로 또는 인라인 (authorized_keys 형식).
+	// 둘 다 비어 있으면 "<key_file>-cert.pub" 가 있을 때 사용합니다
+	CertFile    string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`
+	Certificate string `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+
+	// 호스트 인증서를 검증할 CA 공개키 (인라인 또는 파일 경로).
+	// 설정하면 호스트별 지문(known_hosts) 대신 CA 서명을 확인합니다
+	HostCA string `json:"host_ca,omitempty" yaml:"host_ca,omitempty"`
+
+	// ssh-agent 소켓 경로 (auth_order 에 agent 가 명시되면 비어있을 때 SSH_AUTH_SOCK 사용)
+	AgentSocket string `json:"agent_socket,omitempty" yaml:"agent_socket,omitempty"`
+
+	// 인증 시도 순서 (agent, key, password). 비어있으면 설정된 수단을 agent → key → password 순으로 시도
+	AuthOrder []string `json:"auth_order,omitempty" yaml:"auth_order,omitempty"`
+
+	// 순서대로 거쳐갈 점프 호스트 (ProxyJump). 각 호스트는 자체 인증 정보를 가집니다
+	Jump []ConnectionConfig `json:"jump,omitempty" yaml:"jump,omitempty"`
+
+	// compose 명령 형태 (docker, docker-compose, podman-compose, podman). 비어있으면 자동 감지
+	Runtime string `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+
+	// docker/git 등 권한이 필요한 명령의 sudo 실행 설정
+	Sudo SudoConfig `json:"sudo" yaml:"sudo,omitempty"`
+
+	// keepalive 요청 주기 (기본 30s, 음수면 사용 안 함). NAT 등에서 유휴 연결이 끊기는 것을 막고,
+	// KeepAliveMaxMissed 번 연속으로 응답이 없으면 연결이 끊어진 것으로 판단합니다 (기본 3)
+	KeepAlive          time.Duration `json:"keepalive,omitempty" yaml:"keepalive,omitempty"`
+	KeepAliveMaxMissed int           `json:"keepalive_max_missed,omitempty" yaml:"keepalive_max_missed,omitempty"`
 }
 
+// NewClient known_hosts 에 등록된 호스트에만 연결합니다
 func NewClient(config ConnectionConfig) (*Client, error) {
-	sshConfig := &ssh.ClientConfig{
-		User: config.User,
-		Auth: []ssh.AuthMethod{
-			ssh.Password(config.Password),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         10 * time.Second,
+	return newClient(config, false)
+}
+
+// NewClientPinningHostKey NewClient 와 같지만 등록되지 않은 호스트(점프 호스트 포함)의 키를 known_hosts 에 기록합니다.
+// 운영자가 직접 연결을 테스트할 때만 사용합니다
+func NewClientPinningHostKey(config ConnectionConfig) (*Client, error) {
+	return newClient(config, true)
+}
+
+func newClient(config ConnectionConfig, pinNew bool) (*Client, error) {
+	c := &Client{
+		host: config.Host,
+		port: config.Port,
+		sudo: config.Sudo,
+
+		runtimeName: config.Runtime,
+		runtime:     &runtimeCache{},
 	}
 
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	if err := c.dial(config, pinNew); err != nil {
+		c.Close()
+		return nil, err
+	}
 
-	client, err := ssh.Dial("tcp", addr, sshConfig)
-	if err != nil {
-		return nil, fmt.Errorf("SSH 연결 실패: %v", err)
+	c.health = newConnHealth()
+	c.startKeepAlive(config.KeepAlive, config.KeepAliveMaxMissed)
+
+	return c, nil
+}
+
+// HostKeyFingerprint 연결된 서버의 호스트 키 SHA256 지문
+func (c *Client) HostKeyFingerprint() string {
+	if c.hostKey == nil {
+		return ""
 	}
+	return ssh.FingerprintSHA256(c.hostKey)
+}
 
-	return &Client{
-		client: client,
-		config: sshConfig,
-		host:   config.Host,
-		port:   config.Port,
-	}, nil
+// HostKeyPinned 이번 연결에서 처음으로 호스트 키가 등록되었는지 여부
+func (c *Client) HostKeyPinned() bool {
+	return c.hostKeyPinned
 }
 
-func (c *Client) ExecuteCommand(command string) (string, error) {
+func (c *Client) newSession() (*ssh.Session, error) {
+	if err := c.lostError(); err != nil {
+		if c.entry != nil {
+			c.pool.markBroken(c.entry)
+		}
+		return nil, fmt.Errorf("세션 생성 실패: %w", err)
+	}
+
 	session, err := c.client.NewSession()
 	if err != nil {
-		return "", fmt.Errorf("세션 생성 실패: %v", err)
+		// 채널 거부(MaxSessions 초과 등)가 아니면 연결이 끊어진 것으로 간주
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) {
+			return nil, fmt.Errorf("세션 생성 실패: %v", err)
+		}
+		if c.health != nil {
+			c.health.markLost(err)
+		}
+		if c.entry != nil {
+			c.pool.markBroken(c.entry)
+		}
+		return nil, fmt.Errorf("세션 생성 실패: %w: %v", ErrConnectionLost, err)
 	}
-	defer session.Close()
+	if c.entry != nil {
+		atomic.AddInt64(&c.entry.sessions, 1)
+	}
+	return session, nil
+}
 
-	output, err := session.CombinedOutput(command)
-	if err != nil {
-		return string(output), fmt.Errorf("명령어 실행 실패: %v\n출력: %s", err, string(output))
+func (c *Client) closeSession(session *ssh.Session) {
+	session.Close()
+	if c.entry != nil {
+		atomic.AddInt64(&c.entry.sessions, -1)
 	}
+}
+
+// ExecuteCommand 셸 명령 문자열을 실행합니다. sudo 설정은 Raw 로 나눈 각 단계에 적용되므로 가능하면 Execute 를 사용합니다
+func (c *Client) ExecuteCommand(ctx context.Context, command string) (*CommandResult, error) {
+	return c.Execute(ctx, Raw(command))
+}
 
-	return string(output), nil
+// run 명령을 실행하고 결과를 반환합니다. 명령이 시작된 경우 에러가 있어도 결과는 nil 이 아닙니다.
+// 0 이 아닌 종료 코드는 *CommandError 로 반환됩니다.
+func (c *Client) run(ctx context.Context, command string, stdin io.Reader) (*CommandResult, error) {
+	session, err := c.newSession()
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeSession(session)
+
+	var stdout, stderr bytes.Buffer
+	var output syncBuffer
+	session.Stdin = stdin
+	// ssh 패키지는 stdout/stderr 를 각각 하나의 고루틴에서 복사하므로 개별 버퍼는 잠금이 필요 없음
+	session.Stdout = io.MultiWriter(&stdout, &output)
+	session.Stderr = io.MultiWriter(&stderr, &output)
+
+	result := &CommandResult{Command: command}
+	start := time.Now()
+	err = c.connectionError(ctx, runSession(ctx, session, command))
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	result.Output = output.String()
+	return result, result.finish(start, err)
 }
 
-func (c *Client) ExecuteCommands(commands []string) ([]string, error) {
+func (c *Client) ExecuteCommands(ctx context.Context, commands []string) ([]string, error) {
 	var results []string
 
 	for i, command := range commands {
 		fmt.Printf("  [%d/%d] %s\n", i+1, len(commands), command)
 
-		output, err := c.ExecuteCommand(command)
+		result, err := c.ExecuteCommand(ctx, command)
 		if err != nil {
 			return results, fmt.Errorf("명령어 실행 실패 (단계 %d): %v", i+1, err)
 		}
 
-		results = append(results, strings.TrimSpace(output))
+		results = append(results, strings.TrimSpace(result.Stdout))
 	}
 
 	return results, nil
 }
 
-func (c *Client) CheckConnection() error {
-	_, err := c.ExecuteCommand("echo 'connection test'")
+// Execute 인용된 명령을 실행하고 결과를 반환합니다. sudo 설정이 있으면 대상 명령에 적용합니다
+func (c *Client) Execute(ctx context.Context, cmd Command) (*CommandResult, error) {
+	cmd, stdin := c.sudo.apply(cmd)
+	return c.run(ctx, cmd.String(), stdin)
+}
+
+// ExecuteWithStreaming 인용된 명령을 실행하며 출력을 output 으로 전달합니다. sudo 설정이 있으면 대상 명령에 적용합니다
+func (c *Client) ExecuteWithStreaming(ctx context.Context, cmd Command, output io.Writer) (*CommandResult, error) {
+	cmd, stdin := c.sudo.apply(cmd)
+	return c.runStreaming(ctx, cmd.String(), stdin, output)
+}
+
+func (c *Client) CheckConnection(ctx context.Context) error {
+	_, err := c.Execute(ctx, Cmd("echo", "connection test"))
 	return err
 }
 
-func (c *Client) GetDockerContainerStatus(containerName string) (string, error) {
-	command := fmt.Sprintf("docker ps --filter name=%s --format 'table {{.Names}}\t{{.Status}}\t{{.Ports}}'", containerName)
-	return c.ExecuteCommand(command)
+func (c *Client) GetDockerContainerStatus(ctx context.Context, containerName string) (string, error) {
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return "", err
+	}
+	result, err := c.Execute(ctx, rt.engine("ps", "--filter", "name="+containerName,
+		"--format", "table {{.Names}}\t{{.Status}}\t{{.Ports}}"))
+	if err != nil {
+		return "", err
+	}
+	return result.Stdout, nil
 }
 
-func (c *Client) GetDockerLogs(containerName string, lines int) (string, error) {
-	command := fmt.Sprintf("docker logs --tail %d %s", lines, containerName)
-	return c.ExecuteCommand(command)
+func (c *Client) GetDockerLogs(ctx context.Context, containerName string, lines int) (string, error) {
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return "", err
+	}
+	// docker logs 는 컨테이너의 stderr 를 그대로 stderr 로 내보내므로 합친 출력 사용
+	result, err := c.Execute(ctx, rt.engine("logs", "--tail", strconv.Itoa(lines), "--", containerName))
+	if err != nil {
+		return "", err
+	}
+	return result.Output, nil
 }
 
-func (c *Client) CheckServiceHealth(url string) (string, error) {
-	command := fmt.Sprintf("curl -s -o /dev/null -w '%%{http_code}' %s || echo 'connection_failed'", url)
-	return c.ExecuteCommand(command)
+func (c *Client) CheckServiceHealth(ctx context.Context, url string) (string, error) {
+	result, err := c.Execute(ctx, Cmd("curl", "-s", "-o", "/dev/null", "-w", "%{http_code}", "--", url))
+	if _, ok := ExitStatus(err); ok {
+		return "connection_failed", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.Stdout, nil
 }
 
 func (c *Client) Close() error {
+	if c.pool != nil {
+		if c.released.CompareAndSwap(false, true) {
+			c.pool.release(c.entry)
+		}
+		return nil
+	}
+
+	if c.health != nil {
+		c.health.shutdown()
+	}
+
+	var err error
 	if c.client != nil {
-		return c.client.Close()
+		err = c.client.Close()
+	}
+	// 점프 호스트는 안쪽(마지막)부터 닫기
+	for i := len(c.jumps) - 1; i >= 0; i-- {
+		c.jumps[i].Close()
 	}
-	return nil
+	for _, conn := range c.agentConns {
+		conn.Close()
+	}
+	return err
 }
 
-func (c *Client) GitPull(projectPath string, branch string) error {
-	if !isValidPath(projectPath) || !isValidBranch(branch) {
-		return fmt.Errorf("유효하지 않은 프로젝트 경로 또는 브랜치명입니다")
+func (c *Client) GitPull(ctx context.Context, projectPath string, branch string) error {
+	if err := validatePath("프로젝트 경로", projectPath); err != nil {
+		return err
+	}
+	if err := ValidateBranch(branch); err != nil {
+		return err
 	}
 	// 그냥 git pull을 하자. 심플하게.
-	command := fmt.Sprintf("cd %s && git pull origin %s", projectPath, branch)
-
-	_, err := c.ExecuteCommand(command)
+	_, err := c.Execute(ctx, Cmd("git", "pull", "origin", branch).In(projectPath))
 	if err != nil {
 		// pull 실패시 한번 더 시도 (force로)
-		forceCommand := fmt.Sprintf("cd %s && git fetch origin && git reset --hard origin/%s",
-			projectPath, branch)
-		_, err = c.ExecuteCommand(forceCommand)
+		_, err = c.Execute(ctx, Cmd("git", "fetch", "origin").
+			Then(Cmd("git", "reset", "--hard", "origin/"+branch)).
+			In(projectPath))
 	}
 
 	return err
 }
 
-func (c *Client) DockerComposeUp(projectPath string, composeFile string) error {
-	if !isValidPath(projectPath) || !isValidPath(composeFile) {
-		return fmt.Errorf("유효하지 않은 경로 또는 파일명입니다")
+func (c *Client) DockerComposeUp(ctx context.Context, projectPath string, composeFile string) error {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return err
+	}
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return err
 	}
 	// Docker Compose가 알아서 처리
-	command := fmt.Sprintf("cd %s && docker compose -f %s up -d --build",
-		projectPath, composeFile)
-
-	_, err := c.ExecuteCommand(command)
+	_, err = c.Execute(ctx, rt.compose(composeFile, "up", "-d", "--build").In(projectPath))
 	return err
 }
 
-func (c *Client) DockerComposeDown(projectPath string, composeFile string) error {
-	if !isValidPath(projectPath) || !isValidPath(composeFile) {
-		return fmt.Errorf("유효하지 않은 경로 또는 파일명입니다")
+func (c *Client) DockerComposeDown(ctx context.Context, projectPath string, composeFile string) error {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return err
 	}
-	command := fmt.Sprintf("cd %s && docker compose -f %s down",
-		projectPath, composeFile)
-
-	_, err := c.ExecuteCommand(command)
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = c.Execute(ctx, rt.compose(composeFile, "down").In(projectPath))
 	return err
 }
 
-func (c *Client) GetGitCommitHash(projectPath string) (string, error) {
-	if !isValidPath(projectPath) {
-		return "", fmt.Errorf("유효하지 않은 프로젝트 경로입니다")
+func (c *Client) GetGitCommitHash(ctx context.Context, projectPath string) (string, error) {
+	if err := validatePath("프로젝트 경로", projectPath); err != nil {
+		return "", err
 	}
-	command := fmt.Sprintf("cd %s && git rev-parse --short HEAD", projectPath)
-	output, err := c.ExecuteCommand(command)
+	result, err := c.Execute(ctx, Cmd("git", "rev-parse", "--short", "HEAD").In(projectPath))
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(output), nil
+	return strings.TrimSpace(result.Stdout), nil
 }
 
-func (c *Client) CreateBackup(projectPath string) error {
-	if !isValidPath(projectPath) {
-		return fmt.Errorf("유효하지 않은 프로젝트 경로입니다")
+func (c *Client) CreateBackup(ctx context.Context, projectPath string) error {
+	if err := validatePath("프로젝트 경로", projectPath); err != nil {
+		return err
 	}
 	timestamp := time.Now().Format("20060102-150405")
-	command := fmt.Sprintf("cd %s && git rev-parse HEAD > .last_deploy_commit && echo %s > .backup_timestamp",
-		projectPath, timestamp)
-
-	_, err := c.ExecuteCommand(command)
+	_, err := c.Execute(ctx, Cmd("git", "rev-parse", "HEAD").WriteTo(".last_deploy_commit").
+		Then(Cmd("echo", timestamp).WriteTo(".backup_timestamp")).
+		In(projectPath))
 	return err
 }
 
-func (c *Client) ExecuteCommandWithStreaming(command string, output io.Writer) error {
-	session, err := c.client.NewSession()
+// ExecuteCommandWithStreaming ExecuteCommand 와 같지만 출력을 output 으로 전달합니다
+func (c *Client) ExecuteCommandWithStreaming(ctx context.Context, command string, output io.Writer) (*CommandResult, error) {
+	return c.ExecuteWithStreaming(ctx, Raw(command), output)
+}
+
+func (c *Client) runStreaming(ctx context.Context, command string, stdin io.Reader, output io.Writer) (*CommandResult, error) {
+	session, err := c.newSession()
 	if err != nil {
-		return fmt.Errorf("세션 생성 실패: %v", err)
+		return nil, err
 	}
-	defer session.Close()
+	defer c.closeSession(session)
 
-	session.Stdout = output
-	session.Stderr = output
+	session.Stdin = stdin
 
-	return session.Run(command)
+	// stdout/stderr 는 별도 고루틴에서 복사되므로 같은 writer 에 순서대로 쓰이도록 보호
+	w := &lockedWriter{w: output}
+	session.Stdout = w
+	session.Stderr = w
+
+	result := &CommandResult{Command: command}
+	start := time.Now()
+	err = c.connectionError(ctx, runSession(ctx, session, command))
+	return result, result.finish(start, err)
 }
 
-func (c *Client) DockerComposeUpWithStreaming(projectPath string, composeFile string, output io.Writer) error {
-	if !isValidPath(projectPath) || !isValidPath(composeFile) {
-		return fmt.Errorf("유효하지 않은 경로 또는 파일명입니다")
+func (c *Client) DockerComposeUpWithStreaming(ctx context.Context, projectPath string, composeFile string, output io.Writer) error {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return err
 	}
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "🐳 컨테이너 런타임: %s\n", rt)
+
 	// 파일 존재 확인
 	fmt.Fprintf(output, "📋 Docker Compose 파일 확인...\n")
-	checkCmd := fmt.Sprintf("cd %s && ls -la %s", projectPath, composeFile)
-	c.ExecuteCommandWithStreaming(checkCmd, output)
+	c.ExecuteWithStreaming(ctx, Cmd("ls", "-la", "--", composeFile).In(projectPath), output)
 
 	// 기존 컨테이너 확인
 	fmt.Fprintf(output, "\n🔍 기존 컨테이너 확인...\n")
-	psCmd := fmt.Sprintf("cd %s && docker compose -f %s ps", projectPath, composeFile)
-	c.ExecuteCommandWithStreaming(psCmd, output)
+	c.ExecuteWithStreaming(ctx, rt.compose(composeFile, "ps").In(projectPath), output)
 
 	// 안전하게 기존 스택 정리
 	fmt.Fprintf(output, "\n🧹 기존 스택 정리...\n")
-	downCmd := fmt.Sprintf("cd %s && docker compose -f %s down --remove-orphans",
-		projectPath, composeFile)
+	downCmd := rt.compose(composeFile, "down", "--remove-orphans").In(projectPath)
 
-	if err := c.ExecuteCommandWithStreaming(downCmd, output); err != nil {
+	if _, err := c.ExecuteWithStreaming(ctx, downCmd, output); err != nil {
 		fmt.Fprintf(output, "⚠️ Docker Compose down 실패: %v\n", err)
 
 		// 프로젝트명 기반으로 컨테이너 직접 제거 시도
 		fmt.Fprintf(output, "🔧 컨테이너 직접 제거 시도...\n")
+		// xargs 로 넘기면 rm 에 sudo 가 적용되지 않으므로 ID 를 받아 직접 실행
 		projectName := filepath.Base(projectPath)
-		removeCmd := fmt.Sprintf("docker ps -a --filter 'name=%s' -q | xargs -r docker rm -f", projectName)
-		c.ExecuteCommandWithStreaming(removeCmd, output)
+		if ps, err := c.Execute(ctx, rt.engine("ps", "-a", "--filter", "name="+projectName, "-q")); err == nil {
+			if ids := strings.Fields(ps.Stdout); len(ids) > 0 {
+				c.ExecuteWithStreaming(ctx, rt.engine(append([]string{"rm", "-f", "--"}, ids...)...), output)
+			}
+		}
 	}
 
 	fmt.Fprintf(output, "\n🚀 새로운 스택 빌드 및 시작...\n")
-	upCmd := fmt.Sprintf("cd %s && docker compose -f %s up -d --build",
-		projectPath, composeFile)
+	upCmd := rt.compose(composeFile, "up", "-d", "--build").In(projectPath)
 
-	return c.ExecuteCommandWithStreaming(upCmd, output)
+	_, err = c.ExecuteWithStreaming(ctx, upCmd, output)
+	return err
 }
 
-func (c *Client) CheckContainerStatus(projectPath string, composeFile string) (string, error) {
-	if !isValidPath(projectPath) || !isValidPath(composeFile) {
-		return "unknown", fmt.Errorf("유효하지 않은 경로 또는 파일명입니다")
+func (c *Client) CheckContainerStatus(ctx context.Context, projectPath string, composeFile string) (string, error) {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return "unknown", err
+	}
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return "unknown", err
+	}
+
+	if !rt.jsonPS {
+		// docker-compose v1, podman-compose 는 표 형식 상태 (Up 3 minutes, Exit 0, Exited (0) ...)
+		result, err := c.Execute(ctx, rt.compose(composeFile, "ps").In(projectPath))
+		if err != nil {
+			return "unknown", err
+		}
+		if psUp.MatchString(result.Stdout) {
+			return "running", nil
+		} else if psExited.MatchString(result.Stdout) {
+			return "stopped", nil
+		}
+		return "unknown", nil
 	}
-	command := fmt.Sprintf("cd %s && docker compose -f %s ps --format json", projectPath, composeFile)
-	output, err := c.ExecuteCommand(command)
+
+	result, err := c.Execute(ctx, rt.compose(composeFile, "ps", "--format", "json").In(projectPath))
 	if err != nil {
 		return "unknown", err
 	}
 
-	if strings.Contains(output, "running") {
+	if strings.Contains(result.Stdout, "running") {
 		return "running", nil
-	} else if strings.Contains(output, "exited") || strings.Contains(output, "stopped") {
+	} else if strings.Contains(result.Stdout, "exited") || strings.Contains(result.Stdout, "stopped") {
 		return "stopped", nil
 	}
 
 	return "unknown", nil
 }
 
-func (c *Client) GetCurrentCommit(projectPath string) (string, error) {
-	if !isValidPath(projectPath) {
+func (c *Client) GetCurrentCommit(ctx context.Context, projectPath string) (string, error) {
+	if validatePath("프로젝트 경로", projectPath) != nil {
 		return "unknown", nil
 	}
 	// 커밋 해시와 메시지를 함께 가져오기
-	command := fmt.Sprintf("cd %s && git log -1 --pretty=format:'%%h|%%s' 2>/dev/null || echo 'unknown|'", projectPath)
-	output, err := c.ExecuteCommand(command)
+	result, err := c.Execute(ctx, Cmd("git", "log", "-1", "--pretty=format:%h|%s").In(projectPath))
+	if _, ok := ExitStatus(err); ok {
+		// 저장소가 아니거나 커밋이 없는 경우
+		return "unknown|", nil
+	}
 	if err != nil {
 		return "unknown", nil
 	}
-	return strings.TrimSpace(output), nil
+	return strings.TrimSpace(result.Stdout), nil
 }
 
-func (c *Client) GetLastDeployTime(projectPath string) (time.Time, error) {
-	if !isValidPath(projectPath) {
+func (c *Client) GetLastDeployTime(ctx context.Context, projectPath string) (time.Time, error) {
+	if validatePath("프로젝트 경로", projectPath) != nil {
 		return time.Time{}, nil
 	}
-	command := fmt.Sprintf("cd %s && cat .backup_timestamp 2>/dev/null || echo ''", projectPath)
-	output, err := c.ExecuteCommand(command)
-	if err != nil || strings.TrimSpace(output) == "" {
+	// 백업 기록이 없으면 cat 이 실패하므로 배포 시각 없음으로 처리
+	result, err := c.Execute(ctx, Cmd("cat", ".backup_timestamp").In(projectPath))
+	if err != nil || strings.TrimSpace(result.Stdout) == "" {
 		return time.Time{}, nil
 	}
 
-	return time.Parse("20060102-150405", strings.TrimSpace(output))
+	return time.Parse("20060102-150405", strings.TrimSpace(result.Stdout))
 }
 
-func (c *Client) DockerLogs(projectPath string, composeFile string, lines string) (string, error) {
-	if !isValidPath(projectPath) || !isValidPath(composeFile) {
-		return "", fmt.Errorf("유효하지 않은 경로 또는 파일명입니다")
+func (c *Client) DockerLogs(ctx context.Context, projectPath string, composeFile string, lines string) (string, error) {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return "", err
 	}
-	command := fmt.Sprintf("cd %s && docker compose -f %s logs --tail %s",
-		projectPath, composeFile, lines)
-	return c.ExecuteCommand(command)
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return "", err
+	}
+	result, err := c.Execute(ctx, rt.compose(composeFile, "logs", "--tail", lines).In(projectPath))
+	if err != nil {
+		return "", err
+	}
+	return result.Output, nil
+}
+
+// ComposeService compose 파일에 정의된 서비스
+type ComposeService struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
 }
 
-// 유효한 경로 이름인지 확인 (커맨드 인젝션 방지)
-func isValidPath(path string) bool {
-	if path == "" {
-		return false
+// ComposeServices compose 파일에 정의된 서비스 목록과 실행 여부를 조회합니다
+func (c *Client) ComposeServices(ctx context.Context, projectPath string, composeFile string) ([]ComposeService, error) {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return nil, err
 	}
-	// 경로에 세미콜론, 앰퍼샌드, 파이프 등 위험한 문자 금지
-	dangerChars := []string{";", "&", "|", ">", "<", "`", "$", "(", ")"}
-	for _, char := range dangerChars {
-		if strings.Contains(path, char) {
-			return false
-		}
+
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return true
-}
 
-func isValidBranch(branch string) bool {
-	if branch == "" {
-		return false
+	defined, err := c.Execute(ctx, rt.compose(composeFile, "config", "--services").In(projectPath))
+	if err != nil {
+		return nil, err
 	}
-	// 브랜치명에는 공백이나 위험한 문자 금지
-	dangerChars := []string{" ", ";", "&", "|", ">", "<", "`", "$", "(", ")", "*", "?", "[", "]", "{", "}"}
-	for _, char := range dangerChars {
-		if strings.Contains(branch, char) {
-			return false
-		}
+	running, err := c.Execute(ctx, rt.compose(composeFile, "ps", "--services", "--filter", "status=running").In(projectPath))
+	if err != nil {
+		return nil, err
+	}
+
+	isRunning := make(map[string]bool)
+	for _, name := range strings.Fields(running.Stdout) {
+		isRunning[name] = true
+	}
+
+	var services []ComposeService
+	for _, name := range strings.Fields(defined.Stdout) {
+		services = append(services, ComposeService{Name: name, Running: isRunning[name]})
+	}
+	return services, nil
+}
+
+func validateComposePaths(projectPath string, composeFile string) error {
+	if err := validatePath("프로젝트 경로", projectPath); err != nil {
+		return err
 	}
-	return true
+	return validatePath("Docker Compose 파일", composeFile)
 }
@@ -1,22 +1,28 @@
 package ssh
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"strings"
 )
 
 // GetEnvironmentVariables Docker Compose 프로젝트의 환경변수를 조회합니다
-func (c *Client) GetEnvironmentVariables(projectPath string, composeFile string) (map[string]string, error) {
+func (c *Client) GetEnvironmentVariables(ctx context.Context, projectPath string, composeFile string) (map[string]string, error) {
 	envVars := make(map[string]string)
 
 	// 1. 간단하게 .env.production 파일 읽기
-	command := fmt.Sprintf("cat %s/.env.production 2>/dev/null", projectPath)
-	envOutput, err := c.ExecuteCommand(command)
+	var envOutput string
+	result, err := c.Execute(ctx, Cmd("cat", "--", path.Join(projectPath, ".env.production")))
+	if err == nil {
+		envOutput = result.Stdout
+	}
 
 	// 2. .env.production이 없으면 .env 시도
-	if err != nil || envOutput == "" {
-		command = fmt.Sprintf("cat %s/.env 2>/dev/null", projectPath)
-		envOutput, _ = c.ExecuteCommand(command)
+	if envOutput == "" {
+		if result, err := c.Execute(ctx, Cmd("cat", "--", path.Join(projectPath, ".env"))); err == nil {
+			envOutput = result.Stdout
+		}
 	}
 
 	// 3. 환경변수 파싱
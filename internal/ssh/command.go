@@ -0,0 +1,315 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command 원격 셸에서 실행할 명령. 모든 인자는 렌더링 시 POSIX 규칙으로 인용되므로
+// 경로나 브랜치명에 공백, 따옴표, 개행 등이 있어도 하나의 인자로 전달됩니다.
+//
+//	ssh.Cmd("git", "pull", "origin", branch).In(projectPath)
+//	// cd '/srv/my app' && git pull origin 'feature/x'
+type Command struct {
+	dir   string
+	steps []commandStep
+}
+
+type commandStep struct {
+	op       string // 앞 단계와의 연결 연산자 (&&, ||, |, ;)
+	args     []string
+	redirect []string
+	// raw 인용하지 않고 args 뒤에 그대로 쓰는 셸 명령 (Raw)
+	raw string
+}
+
+// program 단계가 실행하는 프로그램 이름
+func (s commandStep) program() string {
+	if len(s.args) > 0 {
+		return s.args[0]
+	}
+	if fields := strings.Fields(s.raw); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// Cmd 프로그램과 인자로 명령을 만듭니다
+func Cmd(name string, args ...string) Command {
+	return Command{
+		steps: []commandStep{{args: append([]string{name}, args...)}},
+	}
+}
+
+// Raw 셸 명령 문자열을 인용하지 않고 그대로 실행하는 명령을 만듭니다.
+// 따옴표 밖의 &&, ||, |, ;, 개행을 기준으로 단계를 나누므로 sudo 설정이 각 단계의 프로그램에 적용됩니다.
+// 서브셸, 명령 치환, heredoc 이 있으면 나누지 않고 하나의 단계로 둡니다
+func Raw(command string) Command {
+	segments, ops, ok := splitShell(command)
+	if !ok {
+		return Command{steps: []commandStep{{raw: strings.TrimSpace(command)}}}
+	}
+	steps := make([]commandStep, len(segments))
+	for i, segment := range segments {
+		steps[i] = commandStep{op: ops[i], raw: segment}
+	}
+	return Command{steps: steps}
+}
+
+// splitShell 따옴표 밖의 연산자로 command 를 나눕니다. ops[i] 는 segments[i] 앞의 연산자이며,
+// 안전하게 나눌 수 없는 구문이 있거나 빈 단계가 생기면 ok 가 false
+func splitShell(command string) (segments, ops []string, ok bool) {
+	var (
+		current strings.Builder
+		op      string
+		quote   rune
+		escaped bool
+	)
+	flush := func(next string) bool {
+		segment := strings.TrimSpace(current.String())
+		if segment == "" {
+			return false
+		}
+		segments = append(segments, segment)
+		ops = append(ops, op)
+		op = next
+		current.Reset()
+		return true
+	}
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '\'':
+			if ch == '\'' {
+				quote = 0
+			}
+		case ch == '\\':
+			escaped = true
+		case quote == '"':
+			if ch == '"' {
+				quote = 0
+			} else if ch == '`' || (ch == '$' && i+1 < len(runes) && runes[i+1] == '(') {
+				return nil, nil, false
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(' || ch == ')' || ch == '{' || ch == '}' || ch == '`':
+			return nil, nil, false
+		case ch == '<' && i+1 < len(runes) && runes[i+1] == '<':
+			return nil, nil, false
+		case ch == '&' && i+1 < len(runes) && runes[i+1] == '&',
+			ch == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			if !flush(string(runes[i : i+2])) {
+				return nil, nil, false
+			}
+			i++
+			continue
+		case ch == '&' && (i+1 < len(runes) && runes[i+1] == '>' || i > 0 && (runes[i-1] == '>' || runes[i-1] == '<')):
+			// 2>&1, &> 같은 리다이렉트
+		case ch == '&':
+			// 백그라운드 실행은 나누지 않음
+			return nil, nil, false
+		case ch == '|' || ch == ';' || ch == '\n':
+			next := string(ch)
+			if ch == '\n' {
+				next = ";"
+			}
+			if strings.TrimSpace(current.String()) == "" && ch == '\n' {
+				continue
+			}
+			if !flush(next) {
+				return nil, nil, false
+			}
+			continue
+		}
+		current.WriteRune(ch)
+	}
+	if quote != 0 || escaped {
+		return nil, nil, false
+	}
+	if strings.TrimSpace(current.String()) == "" {
+		// 끝의 ; 나 개행은 버림
+		if op == ";" && len(segments) > 0 {
+			return segments, ops, true
+		}
+		return nil, nil, false
+	}
+	flush("")
+	return segments, ops, true
+}
+
+// In 명령을 실행하기 전에 dir 로 이동합니다
+func (c Command) In(dir string) Command {
+	c.dir = dir
+	return c
+}
+
+// Then 앞 명령이 성공하면 next 를 실행합니다 (&&)
+func (c Command) Then(next Command) Command {
+	return c.join("&&", next)
+}
+
+// Or 앞 명령이 실패하면 next 를 실행합니다 (||)
+func (c Command) Or(next Command) Command {
+	return c.join("||", next)
+}
+
+// Pipe 앞 명령의 출력을 next 의 입력으로 연결합니다 (|)
+func (c Command) Pipe(next Command) Command {
+	return c.join("|", next)
+}
+
+// WriteTo 마지막 명령의 표준 출력을 file 에 씁니다
+func (c Command) WriteTo(file string) Command {
+	return c.redirect(">", Quote(file))
+}
+
+// Quiet 마지막 명령의 표준 출력과 에러를 모두 버립니다
+func (c Command) Quiet() Command {
+	return c.redirect(">/dev/null", "2>&1")
+}
+
+// NoStderr 마지막 명령의 표준 에러를 버립니다
+func (c Command) NoStderr() Command {
+	return c.redirect("2>/dev/null")
+}
+
+// MergeStderr 마지막 명령의 표준 에러를 표준 출력으로 합칩니다
+func (c Command) MergeStderr() Command {
+	return c.redirect("2>&1")
+}
+
+func (c Command) join(op string, next Command) Command {
+	steps := make([]commandStep, 0, len(c.steps)+len(next.steps))
+	steps = append(steps, c.steps...)
+	for i, step := range next.steps {
+		if i == 0 {
+			step.op = op
+		}
+		steps = append(steps, step)
+	}
+	c.steps = steps
+	return c
+}
+
+func (c Command) redirect(parts ...string) Command {
+	steps := append([]commandStep(nil), c.steps...)
+	last := &steps[len(steps)-1]
+	last.redirect = append(append([]string(nil), last.redirect...), parts...)
+	c.steps = steps
+	return c
+}
+
+// String 셸에 전달할 명령 문자열
+func (c Command) String() string {
+	var b strings.Builder
+	if c.dir != "" {
+		b.WriteString("cd -- ")
+		b.WriteString(Quote(c.dir))
+		b.WriteString(" && ")
+	}
+	for i, step := range c.steps {
+		if i > 0 {
+			b.WriteString(" ")
+			b.WriteString(step.op)
+			b.WriteString(" ")
+		}
+		for j, arg := range step.args {
+			if j > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(Quote(arg))
+		}
+		if step.raw != "" {
+			if len(step.args) > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(step.raw)
+		}
+		for _, r := range step.redirect {
+			b.WriteString(" ")
+			b.WriteString(r)
+		}
+	}
+	return b.String()
+}
+
+// Quote s 가 POSIX 셸에서 그대로 하나의 인자로 해석되도록 인용합니다
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if isShellSafe(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// isShellSafe 인용 없이도 셸이 특수하게 해석하지 않는 문자로만 이루어졌는지 확인
+func isShellSafe(s string) bool {
+	for _, ch := range s {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case strings.ContainsRune("_-+@%:,./", ch):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateBranch git 참조 이름 규칙(check-ref-format)에 맞는지 확인합니다.
+// 인용과 별개로 '-' 로 시작하는 값이 git 옵션으로 해석되는 것을 막습니다.
+// 설정 검증도 같은 규칙을 쓰도록 이 함수를 호출합니다.
+func ValidateBranch(branch string) error {
+	switch {
+	case branch == "":
+		return fmt.Errorf("브랜치명이 비어 있습니다")
+	case strings.HasPrefix(branch, "-"):
+		return fmt.Errorf("브랜치명은 '-' 로 시작할 수 없습니다: %q", branch)
+	case strings.HasPrefix(branch, "/"), strings.HasSuffix(branch, "/"),
+		strings.HasSuffix(branch, "."), strings.HasSuffix(branch, ".lock"),
+		strings.Contains(branch, ".."), strings.Contains(branch, "//"),
+		strings.Contains(branch, "@{"), branch == "@":
+		return fmt.Errorf("유효하지 않은 브랜치명입니다: %q", branch)
+	}
+	for _, ch := range branch {
+		if ch < 0x20 || ch == 0x7f || strings.ContainsRune(" ~^:?*[\\", ch) {
+			return fmt.Errorf("유효하지 않은 브랜치명입니다: %q", branch)
+		}
+	}
+	for _, part := range strings.Split(branch, "/") {
+		if strings.HasPrefix(part, ".") {
+			return fmt.Errorf("브랜치명의 구성 요소는 '.' 으로 시작할 수 없습니다: %q", branch)
+		}
+	}
+	return nil
+}
+
+// validatePath 원격 경로가 비어 있거나 NUL 을 포함하는지 확인합니다
+func validatePath(kind, path string) error {
+	if path == "" {
+		return fmt.Errorf("%s 값이 비어 있습니다", kind)
+	}
+	if strings.ContainsRune(path, 0) {
+		return fmt.Errorf("%s 값에 NUL 문자가 포함되어 있습니다", kind)
+	}
+	return nil
+}
+
+// validateService compose 서비스 이름이 docker compose 옵션으로 해석되지 않는지 확인합니다
+func validateService(service string) error {
+	switch {
+	case service == "":
+		return fmt.Errorf("서비스 이름이 비어 있습니다")
+	case strings.HasPrefix(service, "-"):
+		return fmt.Errorf("서비스 이름은 '-' 로 시작할 수 없습니다: %q", service)
+	case strings.ContainsAny(service, " \t\r\n\x00/"):
+		return fmt.Errorf("유효하지 않은 서비스 이름입니다: %q", service)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package ssh
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCommandString(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  Command
+		want string
+	}{
+		{
+			name: "safe arguments stay unquoted",
+			cmd:  Cmd("git", "pull", "origin", "main").In("/srv/app"),
+			want: "cd -- /srv/app && git pull origin main",
+		},
+		{
+			name: "spaces and parentheses",
+			cmd:  Cmd("docker", "compose", "-f", "docker-compose (prod).yml", "ps").In("/srv/my app"),
+			want: "cd -- '/srv/my app' && docker compose -f 'docker-compose (prod).yml' ps",
+		},
+		{
+			name: "single quote",
+			cmd:  Cmd("echo", "it's"),
+			want: `echo 'it'"'"'s'`,
+		},
+		{
+			name: "operators and redirects",
+			cmd: Cmd("cat", ".backup_timestamp").NoStderr().
+				Or(Cmd("echo", "")).
+				In("/srv/app"),
+			want: "cd -- /srv/app && cat .backup_timestamp 2>/dev/null || echo ''",
+		},
+		{
+			name: "pipeline",
+			cmd:  Cmd("df", "-h", "--", "/srv").Pipe(Cmd("awk", "{print $5}")).Quiet(),
+			want: "df -h -- /srv | awk '{print $5}' >/dev/null 2>&1",
+		},
+		{
+			name: "write to file",
+			cmd:  Cmd("echo", "x").WriteTo("a b"),
+			want: "echo x > 'a b'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmd.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBranch(t *testing.T) {
+	for _, branch := range []string{"main", "feature/x", "release-1.2", "user@fix"} {
+		if err := ValidateBranch(branch); err != nil {
+			t.Errorf("ValidateBranch(%q) = %v", branch, err)
+		}
+	}
+	for _, branch := range []string{"", "-x", "--upload-pack=touch /tmp/x", "a..b", "a b", "a\nb", "x.lock", "a:b", ".hidden", "feature/.x"} {
+		if err := ValidateBranch(branch); err == nil {
+			t.Errorf("ValidateBranch(%q) = nil, want error", branch)
+		}
+	}
+}
+
+var shellSeeds = []string{
+	"",
+	"plain",
+	"with space",
+	"it's",
+	`"double"`,
+	"$(touch /tmp/pwned)",
+	"`id`",
+	"a;b",
+	"a && b",
+	"line\nbreak",
+	"tab\there",
+	"glob*?[x]",
+	"~root",
+	"$HOME",
+	"back\\slash",
+	"'; rm -rf / #",
+	"-n",
+	"FOO=bar",
+}
+
+// runShell sh 로 명령을 실행하고 출력을 반환합니다
+func runShell(t *testing.T, command string) string {
+	t.Helper()
+	out, err := exec.Command("sh", "-c", command).CombinedOutput()
+	if err != nil {
+		t.Fatalf("sh -c %q: %v\n%s", command, err, out)
+	}
+	return string(out)
+}
+
+func requireShell(t testing.TB) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh 를 찾을 수 없습니다")
+	}
+}
+
+// FuzzQuote 인용된 인자가 셸에서 원래 값 그대로 하나의 인자로 전달되는지 확인합니다
+func FuzzQuote(f *testing.F) {
+	requireShell(f)
+	for _, seed := range shellSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if strings.ContainsRune(s, 0) {
+			t.Skip("셸 인자는 NUL 을 포함할 수 없습니다")
+		}
+		got := runShell(t, "printf '%s|' "+Quote(s))
+		if got != s+"|" {
+			t.Fatalf("Quote(%q) = %s, 셸 출력 %q", s, Quote(s), got)
+		}
+	})
+}
+
+// FuzzCommand 여러 인자와 디렉토리가 조합돼도 추가 명령이 실행되지 않는지 확인합니다
+func FuzzCommand(f *testing.F) {
+	requireShell(f)
+	for _, seed := range shellSeeds {
+		f.Add(seed, "branch")
+		f.Add("x", seed)
+	}
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if strings.ContainsRune(a, 0) || strings.ContainsRune(b, 0) {
+			t.Skip("셸 인자는 NUL 을 포함할 수 없습니다")
+		}
+		cmd := Cmd("printf", "[%s]", a, b).
+			Then(Cmd("printf", "[%s]", "done")).
+			In("/")
+		got := runShell(t, cmd.String())
+		if want := "[" + a + "][" + b + "][done]"; got != want {
+			t.Fatalf("%s\n출력 %q, want %q", cmd, got, want)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const dialTimeout = 10 * time.Second
+
+// dial 점프 호스트를 순서대로 거쳐 최종 서버까지 연결합니다. pinNew 면 등록되지 않은 호스트 키를 기록합니다.
+// 중간에 실패해도 이미 열린 연결은 c 에 남아 있으므로 호출자가 Close 해야 합니다.
+func (c *Client) dial(config ConnectionConfig, pinNew bool) error {
+	hops := append(append([]ConnectionConfig{}, config.Jump...), config)
+
+	var prev *ssh.Client
+	for i, hop := range hops {
+		last := i == len(hops)-1
+		if hop.Port == 0 {
+			hop.Port = 22
+		}
+
+		label := "SSH"
+		if !last {
+			label = fmt.Sprintf("점프 호스트 %s", hop.Host)
+		}
+
+		auth, agentConn, err := hop.authMethods()
+		if err != nil {
+			return fmt.Errorf("%s 인증 설정 오류: %v", label, err)
+		}
+		if agentConn != nil {
+			c.agentConns = append(c.agentConns, agentConn)
+		}
+
+		var (
+			seen   ssh.PublicKey
+			pinned bool
+		)
+		hostKeyCallback := HostKeys().callback(hop.Host, hop.Port, pinNew, &seen, &pinned)
+		if hop.HostCA != "" {
+			// CA 로 검증하는 호스트는 known_hosts 에 지문을 남기지 않음
+			if hostKeyCallback, err = hop.hostCACallback(&seen); err != nil {
+				return fmt.Errorf("%s 인증 설정 오류: %v", label, err)
+			}
+		}
+		sshConfig := &ssh.ClientConfig{
+			User:            hop.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         dialTimeout,
+		}
+
+		client, err := dialHop(prev, hop, sshConfig)
+		if err != nil {
+			var mismatch *HostKeyMismatchError
+			if errors.As(err, &mismatch) {
+				return mismatch
+			}
+			var unknown *HostKeyUnknownError
+			if errors.As(err, &unknown) {
+				return unknown
+			}
+			return fmt.Errorf("%s 연결 실패: %v", label, err)
+		}
+
+		if last {
+			c.client = client
+			c.config = sshConfig
+			c.hostKey = seen
+			c.hostKeyPinned = pinned
+		} else {
+			c.jumps = append(c.jumps, client)
+		}
+		prev = client
+	}
+
+	return nil
+}
+
+// dialHop prev 가 nil 이면 직접, 아니면 prev 를 통한 direct-tcpip 채널로 연결합니다
+func dialHop(prev *ssh.Client, hop ConnectionConfig, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	addr := net.JoinHostPort(hop.Host, strconv.Itoa(hop.Port))
+	if prev == nil {
+		return ssh.Dial("tcp", addr, sshConfig)
+	}
+
+	conn, err := prev.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 터널 채널은 deadline 을 지원하지 않으므로 핸드셰이크가 길어지면 직접 끊습니다
+	timer := time.AfterFunc(dialTimeout, func() { conn.Close() })
+	ncc, chans, reqs, err := ssh.NewClientConn(conn, addr, sshConfig)
+	timer.Stop()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return ssh.NewClient(ncc, chans, reqs), nil
+}
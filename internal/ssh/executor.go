@@ -0,0 +1,89 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// RemoteExecutor 원격 서버에서 배포 관련 명령을 실행하는 기능. *Client 가 구현합니다
+type RemoteExecutor interface {
+	ExecuteCommand(ctx context.Context, command string) (*CommandResult, error)
+	ExecuteCommandWithStreaming(ctx context.Context, command string, output io.Writer) (*CommandResult, error)
+	Execute(ctx context.Context, cmd Command) (*CommandResult, error)
+	ExecuteWithStreaming(ctx context.Context, cmd Command, output io.Writer) (*CommandResult, error)
+	CheckConnection(ctx context.Context) error
+
+	GitPull(ctx context.Context, projectPath string, branch string) error
+	GetCurrentCommit(ctx context.Context, projectPath string) (string, error)
+	CreateBackup(ctx context.Context, projectPath string) error
+	GetLastDeployTime(ctx context.Context, projectPath string) (time.Time, error)
+
+	ContainerRuntime(ctx context.Context) (Runtime, error)
+	DockerComposeUp(ctx context.Context, projectPath string, composeFile string) error
+	DockerComposeUpWithStreaming(ctx context.Context, projectPath string, composeFile string, output io.Writer) error
+	CheckContainerStatus(ctx context.Context, projectPath string, composeFile string) (string, error)
+	DockerLogs(ctx context.Context, projectPath string, composeFile string, lines string) (string, error)
+	GetEnvironmentVariables(ctx context.Context, projectPath string, composeFile string) (map[string]string, error)
+
+	Upload(ctx context.Context, remotePath string, r io.Reader, opts WriteOptions) error
+	Download(ctx context.Context, remotePath string, w io.Writer) error
+	Stat(ctx context.Context, remotePath string) (*FileInfo, error)
+	ListDir(ctx context.Context, remoteDir string) ([]FileInfo, error)
+
+	DialRemote(ctx context.Context, network, addr string) (net.Conn, error)
+	ProbeHTTP(ctx context.Context, url string) (int, error)
+
+	OpenShell(ctx context.Context, opts ShellOptions) (*Shell, error)
+	ComposeServices(ctx context.Context, projectPath, composeFile string) ([]ComposeService, error)
+	ComposeExec(ctx context.Context, projectPath, composeFile, service string, command []string, opts ShellOptions) (*Shell, error)
+
+	Close() error
+}
+
+// Connector 서버 설정으로 RemoteExecutor 를 얻습니다. *Pool 이 구현합니다
+type Connector interface {
+	Connect(config ConnectionConfig) (RemoteExecutor, error)
+}
+
+// ConnectorFunc 함수를 Connector 로 사용하기 위한 어댑터
+type ConnectorFunc func(config ConnectionConfig) (RemoteExecutor, error)
+
+func (f ConnectorFunc) Connect(config ConnectionConfig) (RemoteExecutor, error) {
+	return f(config)
+}
+
+// DirectConnector 풀을 거치지 않고 매번 새 연결을 맺습니다
+var DirectConnector = ConnectorFunc(func(config ConnectionConfig) (RemoteExecutor, error) {
+	return NewClient(config)
+})
+
+var (
+	_ RemoteExecutor = (*Client)(nil)
+	_ Connector      = (*Pool)(nil)
+)
+
+// maxReconnects 읽기 전용 작업을 다시 시도할 최대 횟수
+const maxReconnects = 1
+
+// WithReconnect config 서버에 연결해 fn 을 실행합니다. 연결이 끊어져(ErrConnectionLost) 실패하면
+// 새 연결로 다시 시도하므로, 상태/로그 조회처럼 다시 실행해도 안전한 읽기 전용 작업에만 사용합니다.
+func WithReconnect(connector Connector, config ConnectionConfig, fn func(RemoteExecutor) error) error {
+	for attempt := 0; ; attempt++ {
+		client, err := connector.Connect(config)
+		if err != nil {
+			return fmt.Errorf("SSH 연결 실패: %w", err)
+		}
+
+		err = fn(client)
+		client.Close()
+
+		if err == nil || !errors.Is(err, ErrConnectionLost) || attempt >= maxReconnects {
+			return err
+		}
+		fmt.Printf("SSH 연결이 끊어져 다시 연결합니다 (%s@%s): %v\n", config.User, config.Host, err)
+	}
+}
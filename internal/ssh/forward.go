@@ -0,0 +1,106 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DialRemote 서버 입장에서 addr 로 TCP 연결을 엽니다 (direct-tcpip).
+// 서버의 127.0.0.1 에만 바인딩된 서비스에도 접근할 수 있습니다.
+func (c *Client) DialRemote(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := c.client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("원격 포트 연결 실패 (%s): %v", addr, err)
+	}
+	return conn, nil
+}
+
+// HTTPClient 모든 요청을 SSH 연결을 통해 서버에서 보내는 HTTP 클라이언트.
+// URL 의 localhost 는 sship 호스트가 아닌 원격 서버를 가리킵니다.
+func (c *Client) HTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: c.DialRemote,
+			// 빌린 연결이 반납된 뒤에도 유휴 커넥션이 남지 않도록
+			DisableKeepAlives: true,
+		},
+	}
+}
+
+// ProbeHTTP 서버를 통해 url 에 GET 요청을 보내고 상태 코드를 반환합니다
+func (c *Client) ProbeHTTP(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("잘못된 URL 입니다: %v", err)
+	}
+
+	resp, err := c.HTTPClient(0).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// ForwardLocal localAddr 에서 받은 연결을 서버의 remoteAddr 로 전달합니다 (ssh -L).
+// ctx 가 끝나거나 반환된 리스너를 닫으면 포워딩이 중단됩니다.
+func (c *Client) ForwardLocal(ctx context.Context, localAddr, remoteAddr string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("로컬 포트 리스닝 실패 (%s): %v", localAddr, err)
+	}
+
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+
+	go func() {
+		defer stop()
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				remote, err := c.DialRemote(ctx, "tcp", remoteAddr)
+				if err != nil {
+					fmt.Printf("포트 포워딩 실패: %v\n", err)
+					local.Close()
+					return
+				}
+				pipe(local, remote)
+			}()
+		}
+	}()
+
+	return listener, nil
+}
+
+// pipe 양방향으로 복사하고 한쪽이 끝나면 둘 다 닫습니다
+func pipe(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
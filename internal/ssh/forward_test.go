@@ -0,0 +1,83 @@
+package ssh_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestProbeHTTP(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer backend.Close()
+
+	srv := sshtest.NewServer(t)
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	status, err := client.ProbeHTTP(ctx, backend.URL+"/health")
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("ProbeHTTP() = %d, %v", status, err)
+	}
+	status, err = client.ProbeHTTP(ctx, backend.URL+"/missing")
+	if err != nil || status != http.StatusNotFound {
+		t.Fatalf("ProbeHTTP() = %d, %v", status, err)
+	}
+
+	// 리스닝하지 않는 포트는 연결 실패로 보고
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := ln.Addr().String()
+	ln.Close()
+	if _, err := client.ProbeHTTP(ctx, "http://"+closedAddr+"/"); err == nil {
+		t.Fatal("닫힌 포트에 대한 ProbeHTTP() 가 성공했습니다")
+	}
+}
+
+func TestForwardLocal(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "forwarded")
+	}))
+	defer backend.Close()
+
+	srv := sshtest.NewServer(t)
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener, err := client.ForwardLocal(ctx, "127.0.0.1:0", strings.TrimPrefix(backend.URL, "http://"))
+	if err != nil {
+		t.Fatalf("ForwardLocal() error = %v", err)
+	}
+	defer listener.Close()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("포워딩된 포트 요청 실패: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "forwarded" {
+		t.Fatalf("응답 = %q", body)
+	}
+}
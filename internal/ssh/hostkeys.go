@@ -0,0 +1,266 @@
+package ssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKeyStore sship 이 관리하는 known_hosts 파일 기반 호스트 키 저장소.
+// 등록되지 않은 호스트와 키가 바뀐 호스트는 연결을 거부합니다. 키는 운영자가 연결 테스트나
+// known-hosts API 로 직접 등록한 경우에만 기록됩니다.
+type HostKeyStore struct {
+	path string
+	mu   sync.RWMutex
+	keys map[string]ssh.PublicKey
+}
+
+type KnownHost struct {
+	Address     string `json:"address"`
+	KeyType     string `json:"key_type"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// HostKeyMismatchError 저장된 키와 서버가 제시한 키가 다를 때 반환됩니다
+type HostKeyMismatchError struct {
+	Address  string
+	Expected string
+	Actual   string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("호스트 키가 일치하지 않습니다 (%s): 저장된 키 %s, 서버 키 %s. "+
+		"서버를 재구축한 경우 관리자가 호스트 키를 다시 등록해야 합니다", e.Address, e.Expected, e.Actual)
+}
+
+// HostKeyUnknownError known_hosts 에 등록되지 않은 호스트에 연결할 때 반환됩니다
+type HostKeyUnknownError struct {
+	Address     string
+	Fingerprint string
+}
+
+func (e *HostKeyUnknownError) Error() string {
+	return fmt.Sprintf("등록되지 않은 호스트입니다 (%s, 서버 키 %s). "+
+		"연결 테스트나 POST /api/v1/known-hosts 로 지문을 확인한 뒤 호스트 키를 등록해야 합니다", e.Address, e.Fingerprint)
+}
+
+var (
+	hostKeysMu sync.RWMutex
+	hostKeys   = &HostKeyStore{keys: make(map[string]ssh.PublicKey)}
+)
+
+// UseKnownHostsFile 기본 호스트 키 저장소를 지정한 파일로 교체합니다
+func UseKnownHostsFile(path string) error {
+	store, err := NewHostKeyStore(path)
+	if err != nil {
+		return err
+	}
+	hostKeysMu.Lock()
+	hostKeys = store
+	hostKeysMu.Unlock()
+	return nil
+}
+
+// HostKeys 현재 사용 중인 호스트 키 저장소를 반환합니다
+func HostKeys() *HostKeyStore {
+	hostKeysMu.RLock()
+	defer hostKeysMu.RUnlock()
+	return hostKeys
+}
+
+// NewHostKeyStore path 의 known_hosts 파일을 읽어 저장소를 만듭니다. 파일이 없으면 빈 저장소로 시작합니다.
+func NewHostKeyStore(path string) (*HostKeyStore, error) {
+	s := &HostKeyStore{
+		path: path,
+		keys: make(map[string]ssh.PublicKey),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("known_hosts 파일을 읽을 수 없습니다: %v", err)
+	}
+
+	for len(bytes.TrimSpace(data)) > 0 {
+		marker, hosts, key, _, rest, err := ssh.ParseKnownHosts(data)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("known_hosts 파싱 실패: %v", err)
+		}
+		if marker == "" {
+			for _, host := range hosts {
+				s.keys[host] = key
+			}
+		}
+		data = rest
+	}
+
+	return s, nil
+}
+
+// Lookup 주소에 저장된 호스트 키를 조회합니다
+func (s *HostKeyStore) Lookup(host string, port int) (ssh.PublicKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[hostAddress(host, port)]
+	return key, ok
+}
+
+// Pin 주소의 호스트 키를 등록(또는 교체)하고 파일에 저장합니다
+func (s *HostKeyStore) Pin(host string, port int, key ssh.PublicKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[hostAddress(host, port)] = key
+	return s.saveLocked()
+}
+
+// Remove 주소의 호스트 키를 삭제합니다
+func (s *HostKeyStore) Remove(host string, port int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addr := hostAddress(host, port)
+	if _, ok := s.keys[addr]; !ok {
+		return fmt.Errorf("등록된 호스트 키가 없습니다: %s", addr)
+	}
+	delete(s.keys, addr)
+	return s.saveLocked()
+}
+
+func (s *HostKeyStore) List() []KnownHost {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]KnownHost, 0, len(s.keys))
+	for addr, key := range s.keys {
+		result = append(result, KnownHost{
+			Address:     addr,
+			KeyType:     key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(key),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Address < result[j].Address })
+	return result
+}
+
+// callback 호스트 키 검증 콜백. 처음 보는 키는 pinNew 일 때만 기록하고 pinned 에 true 를 남기며,
+// 그렇지 않으면 HostKeyUnknownError 로 거부합니다.
+func (s *HostKeyStore) callback(host string, port int, pinNew bool, seen *ssh.PublicKey, pinned *bool) ssh.HostKeyCallback {
+	addr := hostAddress(host, port)
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		*seen = key
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		known, ok := s.keys[addr]
+		if !ok {
+			if !pinNew {
+				return &HostKeyUnknownError{Address: addr, Fingerprint: ssh.FingerprintSHA256(key)}
+			}
+			s.keys[addr] = key
+			*pinned = true
+			return s.saveLocked()
+		}
+		if !bytes.Equal(known.Marshal(), key.Marshal()) {
+			return &HostKeyMismatchError{
+				Address:  addr,
+				Expected: ssh.FingerprintSHA256(known),
+				Actual:   ssh.FingerprintSHA256(key),
+			}
+		}
+		return nil
+	}
+}
+
+func (s *HostKeyStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	addrs := make([]string, 0, len(s.keys))
+	for addr := range s.keys {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	var buf bytes.Buffer
+	for _, addr := range addrs {
+		buf.WriteString(knownhosts.Line([]string{addr}, s.keys[addr]))
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("known_hosts 디렉토리 생성 실패: %v", err)
+	}
+	if err := os.WriteFile(s.path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("known_hosts 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// FetchHostKey 인증 없이 핸드셰이크만 수행하여 서버가 제시하는 호스트 키를 가져옵니다.
+// 점프 호스트가 설정된 경우 점프 호스트까지는 정상적으로 인증하여 연결합니다.
+func FetchHostKey(config ConnectionConfig) (ssh.PublicKey, error) {
+	if config.Port == 0 {
+		config.Port = 22
+	}
+
+	var prev *ssh.Client
+	if n := len(config.Jump); n > 0 {
+		jump := config.Jump[n-1]
+		jump.Jump = config.Jump[:n-1]
+		jumpClient, err := NewClient(jump)
+		if err != nil {
+			return nil, err
+		}
+		defer jumpClient.Close()
+		prev = jumpClient.client
+	}
+
+	var key ssh.PublicKey
+	errCaptured := errors.New("host key captured")
+
+	sshConfig := &ssh.ClientConfig{
+		User: "sship",
+		HostKeyCallback: func(_ string, _ net.Addr, k ssh.PublicKey) error {
+			key = k
+			return errCaptured
+		},
+		Timeout: dialTimeout,
+	}
+
+	client, err := dialHop(prev, config, sshConfig)
+	if client != nil {
+		client.Close()
+	}
+	if key == nil {
+		return nil, fmt.Errorf("호스트 키를 가져올 수 없습니다: %v", err)
+	}
+	return key, nil
+}
+
+func hostAddress(host string, port int) string {
+	if port == 0 {
+		port = 22
+	}
+	return knownhosts.Normalize(net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// FingerprintSHA256 공개키의 SHA256 지문 (OpenSSH 형식)
+func FingerprintSHA256(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
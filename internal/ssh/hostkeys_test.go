@@ -0,0 +1,49 @@
+package ssh_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestUnknownHostKeyRejected(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	cfg := srv.ConnectionConfig()
+	if err := ssh.HostKeys().Remove(cfg.Host, cfg.Port); err != nil {
+		t.Fatal(err)
+	}
+
+	// 배포, 상태 확인 등 일반 연결은 등록되지 않은 호스트를 거부하고 키를 기록하지 않음
+	_, err := ssh.NewClient(cfg)
+	var unknown *ssh.HostKeyUnknownError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("NewClient() error = %v, want HostKeyUnknownError", err)
+	}
+	if unknown.Fingerprint != ssh.FingerprintSHA256(srv.HostKey()) {
+		t.Errorf("보고된 지문 = %s", unknown.Fingerprint)
+	}
+	if _, ok := ssh.HostKeys().Lookup(cfg.Host, cfg.Port); ok {
+		t.Fatal("거부한 호스트 키가 등록됨")
+	}
+
+	// 운영자의 연결 테스트에서만 등록
+	client, err := ssh.NewClientPinningHostKey(cfg)
+	if err != nil {
+		t.Fatalf("NewClientPinningHostKey() error = %v", err)
+	}
+	client.Close()
+	if !client.HostKeyPinned() {
+		t.Error("HostKeyPinned() = false")
+	}
+	if _, ok := ssh.HostKeys().Lookup(cfg.Host, cfg.Port); !ok {
+		t.Fatal("호스트 키가 등록되지 않음")
+	}
+
+	client, err = ssh.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("등록 후 NewClient() error = %v", err)
+	}
+	client.Close()
+}
@@ -0,0 +1,149 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	defaultKeepAlive          = 30 * time.Second
+	defaultKeepAliveMaxMissed = 3
+)
+
+// ErrConnectionLost keepalive 응답이 없거나 서버가 연결을 끊어 SSH 연결이 끊어진 경우
+var ErrConnectionLost = errors.New("SSH 연결이 끊어졌습니다")
+
+// connHealth 하나의 SSH 연결 상태. 풀에서 빌린 Client 복사본들이 공유합니다
+type connHealth struct {
+	lost    atomic.Bool
+	closing atomic.Bool
+	stop    chan struct{}
+	once    sync.Once
+	cause   atomic.Value // error
+}
+
+func newConnHealth() *connHealth {
+	return &connHealth{stop: make(chan struct{})}
+}
+
+func (h *connHealth) markLost(cause error) {
+	if h.closing.Load() {
+		return
+	}
+	if h.lost.CompareAndSwap(false, true) {
+		h.cause.Store(cause)
+	}
+}
+
+func (h *connHealth) shutdown() {
+	h.closing.Store(true)
+	h.once.Do(func() { close(h.stop) })
+}
+
+// Lost keepalive 실패 등으로 연결이 끊어진 것으로 판단되었는지 여부
+func (c *Client) Lost() bool {
+	return c.health != nil && c.health.lost.Load()
+}
+
+// lostError 연결이 끊어졌다면 원인을 포함한 ErrConnectionLost 를 반환합니다
+func (c *Client) lostError() error {
+	if !c.Lost() {
+		return nil
+	}
+	if cause, ok := c.health.cause.Load().(error); ok && cause != nil {
+		return fmt.Errorf("%w: %v", ErrConnectionLost, cause)
+	}
+	return ErrConnectionLost
+}
+
+// connectionError 종료 코드 없이 끝난 세션이 연결 끊김 때문이면 ErrConnectionLost 로 바꿉니다
+func (c *Client) connectionError(ctx context.Context, err error) error {
+	var exitErr *ssh.ExitError
+	if err == nil || errors.As(err, &exitErr) || ctx.Err() != nil || c.health == nil {
+		return err
+	}
+
+	// 연결 종료 감지(conn.Wait)가 세션 종료보다 조금 늦을 수 있음
+	select {
+	case <-c.health.stop:
+	case <-time.After(time.Second):
+	}
+	if lost := c.lostError(); lost != nil {
+		if c.entry != nil {
+			c.pool.markBroken(c.entry)
+		}
+		return lost
+	}
+	return err
+}
+
+// startKeepAlive interval 마다 keepalive 요청을 보내고, maxMissed 번 연속으로 응답이 없으면
+// 연결을 닫아 진행 중인 세션이 멈춰 있지 않고 ErrConnectionLost 로 끝나도록 합니다.
+func (c *Client) startKeepAlive(interval time.Duration, maxMissed int) {
+	conn := c.client
+
+	// 서버가 연결을 끊은 경우
+	go func() {
+		err := conn.Wait()
+		c.health.markLost(err)
+		c.health.once.Do(func() { close(c.health.stop) })
+	}()
+
+	if interval < 0 {
+		return
+	}
+	if interval == 0 {
+		interval = defaultKeepAlive
+	}
+	if maxMissed <= 0 {
+		maxMissed = defaultKeepAliveMaxMissed
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		missed := 0
+		for {
+			select {
+			case <-c.health.stop:
+				return
+			case <-ticker.C:
+			}
+
+			if err := sendKeepAlive(conn, interval); err != nil {
+				missed++
+				if missed < maxMissed {
+					continue
+				}
+				c.health.markLost(fmt.Errorf("keepalive 응답 없음 (%d회): %v", missed, err))
+				conn.Close()
+				return
+			}
+			missed = 0
+		}
+	}()
+}
+
+// sendKeepAlive 응답이 timeout 안에 오지 않으면 실패로 봅니다.
+// 끊어진 TCP 연결에서는 SendRequest 가 오래 멈출 수 있으므로 별도 고루틴에서 기다립니다.
+func sendKeepAlive(conn *ssh.Client, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := conn.SendRequest("keepalive@openssh.com", true, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("%v 동안 응답 없음", timeout)
+	}
+}
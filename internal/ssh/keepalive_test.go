@@ -0,0 +1,73 @@
+package ssh_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestKeepAliveDetectsStalledConnection(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("up -d --build", sshtest.Response{Delay: 10 * time.Second})
+
+	config := srv.ConnectionConfig()
+	config.KeepAlive = 100 * time.Millisecond
+	config.KeepAliveMaxMissed = 2
+
+	client, err := ssh.NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	srv.StallKeepAlive(true)
+
+	start := time.Now()
+	err = client.DockerComposeUp(context.Background(), "/srv/app", "docker-compose.prod.yml")
+	if !errors.Is(err, ssh.ErrConnectionLost) {
+		t.Fatalf("DockerComposeUp() error = %v, want ErrConnectionLost", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("연결 끊김 감지에 %v 걸림", elapsed)
+	}
+	if !client.Lost() {
+		t.Error("Lost() = false")
+	}
+}
+
+func TestWithReconnect(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("ps --format json", sshtest.Response{Stdout: `{"State":"running"}`})
+
+	pool := ssh.NewPool(time.Minute, time.Minute)
+	defer pool.Close()
+
+	config := srv.ConnectionConfig()
+	warm, err := pool.Get(config)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	warm.Close()
+
+	// 풀에 남아 있는 연결을 서버가 끊음
+	srv.DropConnections()
+
+	attempts := 0
+	var status string
+	err = ssh.WithReconnect(pool, config, func(client ssh.RemoteExecutor) error {
+		attempts++
+		var err error
+		status, err = client.CheckContainerStatus(context.Background(), "/srv/app", "docker-compose.prod.yml")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithReconnect() error = %v", err)
+	}
+	if status != "running" || attempts != 2 {
+		t.Errorf("status = %q, attempts = %d", status, attempts)
+	}
+}
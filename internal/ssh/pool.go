@@ -0,0 +1,233 @@
+package ssh
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pool 서버별로 하나의 SSH 연결을 공유하는 연결 풀.
+// Get 으로 빌린 Client 의 Close 는 연결을 닫지 않고 풀에 반납합니다.
+type Pool struct {
+	mu          sync.Mutex
+	entries     map[string]*poolEntry
+	idleTimeout time.Duration
+	keepAlive   time.Duration
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+type poolEntry struct {
+	key      string
+	config   ConnectionConfig
+	client   *Client
+	refs     int
+	borrows  int64
+	sessions int64
+	created  time.Time
+	lastUsed time.Time
+	broken   bool
+}
+
+type PoolStats struct {
+	Server         string    `json:"server"`
+	InUse          int       `json:"in_use"`
+	Borrows        int64     `json:"borrows"`
+	ActiveSessions int64     `json:"active_sessions"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastUsed       time.Time `json:"last_used"`
+	Broken         bool      `json:"broken"`
+}
+
+// NewPool idleTimeout 동안 사용되지 않은 연결은 닫고, keepAlive 주기로 연결 상태를 확인합니다.
+// keepAlive 는 접속 설정에 keepalive 가 없는 연결의 기본 keepalive 주기로도 사용됩니다.
+func NewPool(idleTimeout, keepAlive time.Duration) *Pool {
+	p := &Pool{
+		entries:     make(map[string]*poolEntry),
+		idleTimeout: idleTimeout,
+		keepAlive:   keepAlive,
+		done:        make(chan struct{}),
+	}
+
+	go p.maintain()
+
+	return p
+}
+
+// Get 서버에 대한 공유 연결을 빌립니다. 끊어진 연결은 새로 맺습니다.
+func (p *Pool) Get(config ConnectionConfig) (*Client, error) {
+	if config.Port == 0 {
+		config.Port = 22
+	}
+	key := serverKey(config)
+
+	p.mu.Lock()
+	if e, ok := p.entries[key]; ok {
+		if !e.broken && reflect.DeepEqual(e.config, config) {
+			client := p.borrowLocked(e)
+			p.mu.Unlock()
+			return client, nil
+		}
+		// 끊어졌거나 접속 정보가 바뀐 연결은 더 이상 빌려주지 않음
+		p.retireLocked(e)
+	}
+	p.mu.Unlock()
+
+	// 풀의 keepalive 주기를 기본값으로 사용 (비교용 config 는 그대로 유지)
+	dialConfig := config
+	if dialConfig.KeepAlive == 0 {
+		dialConfig.KeepAlive = p.keepAlive
+	}
+	client, err := NewClient(dialConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 연결하는 동안 다른 요청이 먼저 연결을 만들었다면 그것을 사용
+	if e, ok := p.entries[key]; ok && !e.broken && reflect.DeepEqual(e.config, config) {
+		client.Close()
+		return p.borrowLocked(e), nil
+	}
+
+	e := &poolEntry{
+		key:     key,
+		config:  config,
+		client:  client,
+		created: time.Now(),
+	}
+	p.entries[key] = e
+	return p.borrowLocked(e), nil
+}
+
+// Connect Connector 구현
+func (p *Pool) Connect(config ConnectionConfig) (RemoteExecutor, error) {
+	client, err := p.Get(config)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (p *Pool) borrowLocked(e *poolEntry) *Client {
+	e.refs++
+	e.borrows++
+	e.lastUsed = time.Now()
+
+	borrowed := *e.client
+	borrowed.pool = p
+	borrowed.entry = e
+	borrowed.released = new(atomic.Bool)
+	return &borrowed
+}
+
+// release Client.Close 에서 호출되어 빌린 연결을 반납합니다
+func (p *Pool) release(e *poolEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.refs--
+	e.lastUsed = time.Now()
+
+	if e.refs <= 0 && (e.broken || p.entries[e.key] != e) {
+		e.client.Close()
+	}
+}
+
+// markBroken 세션 생성 실패 등으로 연결이 끊어진 것으로 보일 때 호출됩니다
+func (p *Pool) markBroken(e *poolEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retireLocked(e)
+}
+
+func (p *Pool) retireLocked(e *poolEntry) {
+	e.broken = true
+	if p.entries[e.key] == e {
+		delete(p.entries, e.key)
+	}
+	if e.refs <= 0 {
+		e.client.Close()
+	}
+}
+
+// maintain keepalive 요청으로 연결을 유지하고, 유휴 연결을 정리합니다
+func (p *Pool) maintain() {
+	ticker := time.NewTicker(p.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		// keepalive 는 각 연결이 직접 보내므로 여기서는 끊어진 연결과 유휴 연결만 정리
+		p.mu.Lock()
+		for _, e := range p.entries {
+			if err := e.client.lostError(); err != nil {
+				fmt.Printf("SSH 연결 끊김 감지 (%s): %v\n", e.key, err)
+				p.retireLocked(e)
+				continue
+			}
+			if e.refs <= 0 && time.Since(e.lastUsed) > p.idleTimeout {
+				p.retireLocked(e)
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+func (p *Pool) Stats() []PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]PoolStats, 0, len(p.entries))
+	for _, e := range p.entries {
+		stats = append(stats, PoolStats{
+			Server:         e.key,
+			InUse:          e.refs,
+			Borrows:        e.borrows,
+			ActiveSessions: atomic.LoadInt64(&e.sessions),
+			CreatedAt:      e.created,
+			LastUsed:       e.lastUsed,
+			Broken:         e.broken,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Server < stats[j].Server })
+	return stats
+}
+
+// Close 풀의 모든 연결을 닫습니다
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, e := range p.entries {
+			e.client.Close()
+		}
+		p.entries = make(map[string]*poolEntry)
+	})
+}
+
+// serverKey 점프 호스트 경로를 포함한 서버 식별자 (user@host:port via ...)
+func serverKey(config ConnectionConfig) string {
+	key := fmt.Sprintf("%s@%s", config.User, hostAddress(config.Host, config.Port))
+	if len(config.Jump) > 0 {
+		hops := make([]string, len(config.Jump))
+		for i, jump := range config.Jump {
+			hops[i] = serverKey(jump)
+		}
+		key += " via " + strings.Join(hops, ",")
+	}
+	return key
+}
@@ -0,0 +1,75 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// CommandResult 원격 명령 실행 결과.
+// 스트리밍 실행에서는 출력이 writer 로 전달되므로 Stdout/Stderr/Output 이 비어 있습니다.
+type CommandResult struct {
+	Command string `json:"command"`
+	Stdout  string `json:"stdout"`
+	Stderr  string `json:"stderr"`
+	// Output stdout 과 stderr 를 받은 순서대로 합친 출력
+	Output string `json:"output"`
+	// ExitStatus 종료 코드. 종료 코드를 받지 못한 경우(연결 끊김, 취소 등) -1
+	ExitStatus int `json:"exit_status"`
+	// Signal 원격 프로세스가 시그널로 종료된 경우 시그널 이름 (TERM, KILL 등)
+	Signal   string        `json:"signal,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Success 종료 코드 0 으로 끝났는지 여부
+func (r *CommandResult) Success() bool {
+	return r != nil && r.ExitStatus == 0 && r.Signal == ""
+}
+
+// CommandError 명령이 실행되었지만 0 이 아닌 종료 코드나 시그널로 끝난 경우의 에러
+type CommandError struct {
+	Result *CommandResult
+}
+
+func (e *CommandError) Error() string {
+	status := fmt.Sprintf("종료 코드 %d", e.Result.ExitStatus)
+	if e.Result.Signal != "" {
+		status = fmt.Sprintf("시그널 %s", e.Result.Signal)
+	}
+	return fmt.Sprintf("명령어 실행 실패: %s\n출력: %s", status, e.Result.Output)
+}
+
+// ExitStatus err 가 CommandError 이면 종료 코드를 반환합니다
+func ExitStatus(err error) (int, bool) {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Result.ExitStatus, true
+	}
+	return 0, false
+}
+
+// finish 세션 종료 결과를 result 에 기록하고 호출자에게 돌려줄 에러를 만듭니다
+func (r *CommandResult) finish(start time.Time, err error) error {
+	r.Duration = time.Since(start)
+
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+		r.ExitStatus = 0
+		return nil
+	case errors.As(err, &exitErr):
+		r.ExitStatus = exitErr.ExitStatus()
+		r.Signal = exitErr.Signal()
+		return &CommandError{Result: r}
+	default:
+		// 종료 코드를 받기 전에 끝난 경우 (세션 오류, 연결 끊김, 시간 초과 등)
+		r.ExitStatus = -1
+		if strings.TrimSpace(r.Output) == "" {
+			return fmt.Errorf("명령어 실행 실패: %w", err)
+		}
+		return fmt.Errorf("명령어 실행 실패: %w\n출력: %s", err, r.Output)
+	}
+}
@@ -0,0 +1,40 @@
+package ssh_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestExecuteResult(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("git status", sshtest.Response{Stdout: " M README.md\n", Stderr: "warning: x\n"})
+	srv.Handle("git pull", sshtest.Response{Stderr: "fatal: refusing to merge\n", ExitStatus: 128})
+
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	result, err := client.Execute(ctx, ssh.Cmd("git", "status", "--porcelain"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Stdout != " M README.md\n" || result.Stderr != "warning: x\n" || !result.Success() {
+		t.Errorf("Execute() = %+v", result)
+	}
+
+	result, err = client.Execute(ctx, ssh.Cmd("git", "pull"))
+	var cmdErr *ssh.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Result != result {
+		t.Fatalf("Execute() error = %v, want *CommandError", err)
+	}
+	if status, _ := ssh.ExitStatus(err); status != 128 || result.Stderr != "fatal: refusing to merge\n" || result.Success() {
+		t.Errorf("Execute() = %+v", result)
+	}
+}
@@ -0,0 +1,123 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// 컨테이너 런타임 이름 (server.runtime 에 사용)
+const (
+	RuntimeDocker        = "docker"         // docker compose (v2 플러그인)
+	RuntimeDockerCompose = "docker-compose" // docker-compose (v1 독립 실행 파일)
+	RuntimePodmanCompose = "podman-compose"
+	RuntimePodman        = "podman" // podman compose
+)
+
+// ErrNoRuntime 서버에서 사용할 수 있는 compose 명령을 찾지 못한 경우
+var ErrNoRuntime = errors.New("컨테이너 런타임을 찾을 수 없습니다 (docker compose, docker-compose, podman-compose, podman compose)")
+
+// Runtime 원격 서버의 컨테이너 런타임. compose 명령의 형태와 지원 기능이 런타임마다 다릅니다
+type Runtime struct {
+	Name string `json:"name"`
+	// Engine 컨테이너를 직접 다루는 명령 (docker, podman)
+	Engine string `json:"engine"`
+	// Compose compose 하위 명령 앞에 붙는 명령 (예: docker compose)
+	Compose []string `json:"compose"`
+
+	// ps --format json 지원 여부 (compose v2 만 지원)
+	jsonPS bool
+}
+
+// compose ps 표 형식 출력의 상태 열
+var (
+	psUp     = regexp.MustCompile(`\bUp\b`)
+	psExited = regexp.MustCompile(`\bExit(ed)?\b`)
+)
+
+// 감지 순서
+var runtimes = []Runtime{
+	{Name: RuntimeDocker, Engine: "docker", Compose: []string{"docker", "compose"}, jsonPS: true},
+	{Name: RuntimeDockerCompose, Engine: "docker", Compose: []string{"docker-compose"}},
+	{Name: RuntimePodmanCompose, Engine: "podman", Compose: []string{"podman-compose"}},
+	{Name: RuntimePodman, Engine: "podman", Compose: []string{"podman", "compose"}},
+}
+
+func lookupRuntime(name string) (Runtime, bool) {
+	for _, rt := range runtimes {
+		if rt.Name == name {
+			return rt, true
+		}
+	}
+	return Runtime{}, false
+}
+
+func (r Runtime) String() string {
+	return strings.Join(r.Compose, " ")
+}
+
+// compose composeFile 을 사용하는 compose 하위 명령
+func (r Runtime) compose(composeFile string, args ...string) Command {
+	return r.composeCmd(append([]string{"-f", composeFile}, args...)...)
+}
+
+func (r Runtime) composeCmd(args ...string) Command {
+	return Cmd(r.Compose[0], append(append([]string(nil), r.Compose[1:]...), args...)...)
+}
+
+// engine 컨테이너 엔진 명령 (docker ps, podman logs 등)
+func (r Runtime) engine(args ...string) Command {
+	return Cmd(r.Engine, args...)
+}
+
+// runtimeCache 연결별로 감지한 런타임 (풀에서 빌린 Client 복사본과 공유)
+type runtimeCache struct {
+	mu      sync.Mutex
+	runtime *Runtime
+}
+
+// ContainerRuntime 서버의 컨테이너 런타임을 반환합니다. server.runtime 이 설정되어 있지 않으면
+// 첫 호출 시 compose 명령을 차례로 실행해 감지하고, 같은 연결에서는 결과를 재사용합니다.
+func (c *Client) ContainerRuntime(ctx context.Context) (Runtime, error) {
+	if c.runtime == nil {
+		return c.detectRuntime(ctx)
+	}
+
+	c.runtime.mu.Lock()
+	defer c.runtime.mu.Unlock()
+	if c.runtime.runtime != nil {
+		return *c.runtime.runtime, nil
+	}
+
+	rt, err := c.detectRuntime(ctx)
+	if err != nil {
+		return Runtime{}, err
+	}
+	c.runtime.runtime = &rt
+	return rt, nil
+}
+
+func (c *Client) detectRuntime(ctx context.Context) (Runtime, error) {
+	if c.runtimeName != "" {
+		rt, ok := lookupRuntime(c.runtimeName)
+		if !ok {
+			return Runtime{}, fmt.Errorf("알 수 없는 컨테이너 런타임입니다: %s", c.runtimeName)
+		}
+		return rt, nil
+	}
+
+	for _, rt := range runtimes {
+		_, err := c.Execute(ctx, rt.composeCmd("version").Quiet())
+		if err == nil {
+			return rt, nil
+		}
+		// 명령이 없거나 실패하면 다음 후보, 연결 오류는 그대로 반환
+		if _, ok := ExitStatus(err); !ok {
+			return Runtime{}, fmt.Errorf("컨테이너 런타임 감지 실패: %w", err)
+		}
+	}
+	return Runtime{}, ErrNoRuntime
+}
@@ -0,0 +1,90 @@
+package ssh_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestContainerRuntimeDetection(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("compose v1", func(t *testing.T) {
+		srv := sshtest.NewServer(t)
+		srv.Handle("docker compose version", sshtest.Response{ExitStatus: 127})
+		srv.Handle("docker-compose -f docker-compose.prod.yml ps", sshtest.Response{
+			Stdout: "Name   Command   State   Ports\napp_web_1   nginx   Up   80/tcp\n",
+		})
+
+		pool := ssh.NewPool(time.Minute, time.Minute)
+		defer pool.Close()
+
+		for i := 0; i < 2; i++ {
+			client, err := pool.Get(srv.ConnectionConfig())
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if err := client.DockerComposeUp(ctx, "/srv/app", "docker-compose.prod.yml"); err != nil {
+				t.Fatalf("DockerComposeUp() error = %v", err)
+			}
+			status, err := client.CheckContainerStatus(ctx, "/srv/app", "docker-compose.prod.yml")
+			if err != nil || status != "running" {
+				t.Errorf("CheckContainerStatus() = %q, %v, want running", status, err)
+			}
+			client.Close()
+		}
+
+		if !srv.Ran("docker-compose -f docker-compose.prod.yml up -d --build") {
+			t.Errorf("docker-compose v1 명령이 실행되지 않음: %q", srv.Commands())
+		}
+		probes := 0
+		for _, cmd := range srv.Commands() {
+			if strings.Contains(cmd, "version") {
+				probes++
+			}
+		}
+		// 같은 서버 연결에서는 감지 결과를 재사용
+		if probes != 2 {
+			t.Errorf("감지 명령 %d회 실행, want 2 (docker compose, docker-compose): %q", probes, srv.Commands())
+		}
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		srv := sshtest.NewServer(t)
+		config := srv.ConnectionConfig()
+		config.Runtime = ssh.RuntimePodmanCompose
+
+		client, err := ssh.NewClient(config)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		defer client.Close()
+
+		if _, err := client.DockerLogs(ctx, "/srv/app", "compose.yml", "10"); err != nil {
+			t.Fatalf("DockerLogs() error = %v", err)
+		}
+		if got := srv.Commands(); len(got) != 1 || !strings.Contains(got[0], "podman-compose -f compose.yml logs --tail 10") {
+			t.Errorf("commands = %q", got)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		srv := sshtest.NewServer(t)
+		srv.Handle("compose version", sshtest.Response{ExitStatus: 127})
+
+		client, err := ssh.NewClient(srv.ConnectionConfig())
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		defer client.Close()
+
+		if _, err := client.ContainerRuntime(ctx); !errors.Is(err, ssh.ErrNoRuntime) {
+			t.Errorf("ContainerRuntime() error = %v, want ErrNoRuntime", err)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// runSession 명령을 실행하고 완료를 기다립니다.
+// ctx 가 먼저 끝나면 원격 프로세스에 SIGTERM 을 보내고 세션을 닫습니다.
+func runSession(ctx context.Context, session *ssh.Session, command string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := session.Start(command); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// 시그널을 지원하지 않는 서버도 있으므로 세션 종료까지 함께 수행
+		session.Signal(ssh.SIGTERM)
+		session.Close()
+		<-done
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("명령 실행 시간 초과: %w", ctx.Err())
+		}
+		return fmt.Errorf("명령 실행 취소됨: %w", ctx.Err())
+	}
+}
+
+// syncBuffer stdout/stderr 를 하나로 합치기 위한 동시성 안전 버퍼
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
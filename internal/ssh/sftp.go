@@ -0,0 +1,231 @@
+package ssh
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// FileInfo 원격 파일 정보
+type FileInfo struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	IsDir   bool      `json:"is_dir"`
+	ModTime time.Time `json:"mod_time"`
+	UID     int       `json:"uid"`
+	GID     int       `json:"gid"`
+}
+
+// WriteOptions 업로드한 파일에 적용할 권한과 소유자. UID/GID 가 nil 이면 소유자를 바꾸지 않습니다
+type WriteOptions struct {
+	Mode os.FileMode `json:"mode"`
+	UID  *int        `json:"uid,omitempty"`
+	GID  *int        `json:"gid,omitempty"`
+}
+
+const defaultFileMode os.FileMode = 0644
+
+// withSFTP sftp 서브시스템 세션을 열어 fn 을 실행합니다. ctx 가 끝나면 세션을 닫아 전송을 중단합니다
+func (c *Client) withSFTP(ctx context.Context, fn func(*sftp.Client) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	session, err := c.newSession()
+	if err != nil {
+		return err
+	}
+	defer c.closeSession(session)
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("SFTP 세션 생성 실패: %v", err)
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("SFTP 세션 생성 실패: %v", err)
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return fmt.Errorf("SFTP 서브시스템 요청 실패: %v", err)
+	}
+
+	client, err := sftp.NewClientPipe(stdout, stdin)
+	if err != nil {
+		return fmt.Errorf("SFTP 클라이언트 생성 실패: %v", err)
+	}
+	defer client.Close()
+
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	defer stop()
+
+	err = fn(client)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("파일 전송 중단됨: %w", ctxErr)
+	}
+	return err
+}
+
+// Upload r 의 내용을 remotePath 에 씁니다.
+// 같은 디렉토리의 임시 파일에 먼저 쓴 뒤 rename 하므로 중간에 실패해도 기존 파일은 그대로 남습니다.
+func (c *Client) Upload(ctx context.Context, remotePath string, r io.Reader, opts WriteOptions) error {
+	if err := validatePath("원격 파일 경로", remotePath); err != nil {
+		return err
+	}
+	if opts.Mode == 0 {
+		opts.Mode = defaultFileMode
+	}
+
+	return c.withSFTP(ctx, func(client *sftp.Client) error {
+		dir := path.Dir(remotePath)
+		if err := client.MkdirAll(dir); err != nil {
+			return fmt.Errorf("원격 디렉토리 생성 실패 (%s): %v", dir, err)
+		}
+
+		tmpPath := path.Join(dir, fmt.Sprintf(".%s.sship-%s", path.Base(remotePath), randomSuffix()))
+		f, err := client.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+		if err != nil {
+			return fmt.Errorf("임시 파일 생성 실패 (%s): %v", tmpPath, err)
+		}
+
+		committed := false
+		defer func() {
+			if !committed {
+				client.Remove(tmpPath)
+			}
+		}()
+
+		// 내용을 쓰기 전에 권한을 좁혀야 쓰는 동안 다른 사용자가 읽을 수 없음
+		if err := f.Chmod(opts.Mode); err != nil {
+			f.Close()
+			return fmt.Errorf("권한 설정 실패 (%s): %v", remotePath, err)
+		}
+
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return fmt.Errorf("파일 쓰기 실패 (%s): %w", remotePath, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("파일 쓰기 실패 (%s): %v", remotePath, err)
+		}
+
+		if opts.UID != nil || opts.GID != nil {
+			uid, gid := -1, -1
+			if opts.UID != nil {
+				uid = *opts.UID
+			}
+			if opts.GID != nil {
+				gid = *opts.GID
+			}
+			if err := client.Chown(tmpPath, uid, gid); err != nil {
+				return fmt.Errorf("소유자 설정 실패 (%s): %v", remotePath, err)
+			}
+		}
+
+		// posix-rename 은 기존 파일을 원자적으로 덮어씁니다
+		if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+			err = client.PosixRename(tmpPath, remotePath)
+		} else {
+			client.Remove(remotePath)
+			err = client.Rename(tmpPath, remotePath)
+		}
+		if err != nil {
+			return fmt.Errorf("파일 교체 실패 (%s): %v", remotePath, err)
+		}
+
+		committed = true
+		return nil
+	})
+}
+
+// Download remotePath 의 내용을 w 로 복사합니다
+func (c *Client) Download(ctx context.Context, remotePath string, w io.Writer) error {
+	if err := validatePath("원격 파일 경로", remotePath); err != nil {
+		return err
+	}
+
+	return c.withSFTP(ctx, func(client *sftp.Client) error {
+		f, err := client.Open(remotePath)
+		if err != nil {
+			return fmt.Errorf("파일 열기 실패 (%s): %w", remotePath, err)
+		}
+		defer f.Close()
+
+		if _, err := f.WriteTo(w); err != nil {
+			return fmt.Errorf("파일 읽기 실패 (%s): %v", remotePath, err)
+		}
+		return nil
+	})
+}
+
+// Stat 원격 파일 정보를 조회합니다
+func (c *Client) Stat(ctx context.Context, remotePath string) (*FileInfo, error) {
+	if err := validatePath("원격 파일 경로", remotePath); err != nil {
+		return nil, err
+	}
+
+	var info *FileInfo
+	err := c.withSFTP(ctx, func(client *sftp.Client) error {
+		fi, err := client.Stat(remotePath)
+		if err != nil {
+			return fmt.Errorf("파일 정보 조회 실패 (%s): %w", remotePath, err)
+		}
+		info = newFileInfo(remotePath, fi)
+		return nil
+	})
+	return info, err
+}
+
+// ListDir 원격 디렉토리의 항목을 이름순으로 조회합니다
+func (c *Client) ListDir(ctx context.Context, remoteDir string) ([]FileInfo, error) {
+	if err := validatePath("원격 디렉토리 경로", remoteDir); err != nil {
+		return nil, err
+	}
+
+	var entries []FileInfo
+	err := c.withSFTP(ctx, func(client *sftp.Client) error {
+		list, err := client.ReadDirContext(ctx, remoteDir)
+		if err != nil {
+			return fmt.Errorf("디렉토리 조회 실패 (%s): %v", remoteDir, err)
+		}
+		entries = make([]FileInfo, 0, len(list))
+		for _, fi := range list {
+			entries = append(entries, *newFileInfo(path.Join(remoteDir, fi.Name()), fi))
+		}
+		return nil
+	})
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, err
+}
+
+func newFileInfo(remotePath string, fi os.FileInfo) *FileInfo {
+	info := &FileInfo{
+		Name:    fi.Name(),
+		Path:    remotePath,
+		Size:    fi.Size(),
+		Mode:    fi.Mode().String(),
+		IsDir:   fi.IsDir(),
+		ModTime: fi.ModTime(),
+	}
+	if stat, ok := fi.Sys().(*sftp.FileStat); ok {
+		info.UID = int(stat.UID)
+		info.GID = int(stat.GID)
+	}
+	return info
+}
+
+func randomSuffix() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
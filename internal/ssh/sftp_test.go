@@ -0,0 +1,105 @@
+package ssh_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestUploadDownload(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "conf", ".env.production")
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dest, []byte("OLD=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Upload(ctx, dest, strings.NewReader("NEW=1\n"), ssh.WriteOptions{Mode: 0600}); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	data, err := os.ReadFile(dest)
+	if err != nil || string(data) != "NEW=1\n" {
+		t.Fatalf("업로드된 내용 = %q, %v", data, err)
+	}
+	if fi, _ := os.Stat(dest); fi.Mode().Perm() != 0600 {
+		t.Errorf("권한 = %v, want 0600", fi.Mode().Perm())
+	}
+
+	// 임시 파일이 남지 않아야 함
+	entries, err := client.ListDir(ctx, filepath.Dir(dest))
+	if err != nil {
+		t.Fatalf("ListDir() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != ".env.production" {
+		t.Errorf("디렉토리 항목 = %+v", entries)
+	}
+
+	var buf bytes.Buffer
+	if err := client.Download(ctx, dest, &buf); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if buf.String() != "NEW=1\n" {
+		t.Errorf("Download() = %q", buf.String())
+	}
+
+	info, err := client.Stat(ctx, dest)
+	if err != nil || info.Size != 6 || info.IsDir {
+		t.Errorf("Stat() = %+v, %v", info, err)
+	}
+}
+
+// statReader 첫 Read 에서 임시 파일 권한을 확인하는 reader
+type statReader struct {
+	dir   string
+	perms []os.FileMode
+	r     *strings.Reader
+}
+
+func (s *statReader) Read(p []byte) (int, error) {
+	if s.perms == nil {
+		matches, _ := filepath.Glob(filepath.Join(s.dir, ".secret.env.sship-*"))
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err == nil {
+				s.perms = append(s.perms, fi.Mode().Perm())
+			}
+		}
+	}
+	return s.r.Read(p)
+}
+
+func TestUploadRestrictsModeBeforeWriting(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	dir := t.TempDir()
+	r := &statReader{dir: dir, r: strings.NewReader("TOKEN=s3cret\n")}
+	if err := client.Upload(context.Background(), filepath.Join(dir, "secret.env"), r, ssh.WriteOptions{Mode: 0600}); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	// 내용을 쓰는 시점에 임시 파일이 이미 0600 이어야 함
+	if len(r.perms) != 1 || r.perms[0] != 0600 {
+		t.Errorf("쓰는 중 임시 파일 권한 = %v, want [0600]", r.perms)
+	}
+}
@@ -0,0 +1,226 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ShellOptions 대화형 셸 세션 설정
+type ShellOptions struct {
+	// Dir 셸을 시작할 디렉토리. 비어 있으면 로그인 디렉토리
+	Dir  string
+	Term string
+	Cols int
+	Rows int
+}
+
+// Shell PTY 가 할당된 대화형 셸 세션. 표준 에러는 PTY 를 통해 Stdout 으로 합쳐집니다.
+type Shell struct {
+	Stdin  io.WriteCloser
+	Stdout io.Reader
+
+	client    *Client
+	session   *ssh.Session
+	stop      func() bool
+	closeOnce sync.Once
+}
+
+// OpenShell PTY 를 요청하고 로그인 셸을 시작합니다. ctx 가 끝나면 세션을 닫습니다.
+func (c *Client) OpenShell(ctx context.Context, opts ShellOptions) (*Shell, error) {
+	if opts.Dir == "" {
+		return c.startPTY(ctx, opts, "")
+	}
+	// 사용자 셸을 프로젝트 디렉토리에서 로그인 셸로 실행
+	return c.startPTY(ctx, opts, "cd -- "+Quote(opts.Dir)+` && exec "${SHELL:-/bin/sh}" -l`)
+}
+
+// ComposeExec 실행 중인 compose 서비스 컨테이너에서 command 를 대화형으로 실행합니다 (compose exec).
+// command 가 비어 있으면 sh 를 실행합니다.
+func (c *Client) ComposeExec(ctx context.Context, projectPath, composeFile, service string, command []string, opts ShellOptions) (*Shell, error) {
+	if err := validateComposePaths(projectPath, composeFile); err != nil {
+		return nil, err
+	}
+	if err := validateService(service); err != nil {
+		return nil, err
+	}
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+
+	rt, err := c.ContainerRuntime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"exec", service}, command...)
+	cmd := rt.compose(composeFile, args...)
+
+	// PTY 에서는 표준 입력이 곧 컨테이너 셸의 입력이므로 비밀번호를 미리 써 두지 않습니다.
+	// sudo -v 가 프롬프트를 출력한 경우에만 비밀번호를 보내고, 인증이 끝난 뒤 sudo -n 으로 실행합니다
+	noPassword := c.sudo
+	noPassword.Password = ""
+	elevated, _ := noPassword.apply(cmd)
+	needAuth := c.sudo.Enabled && c.sudo.Password != "" && elevated.String() != cmd.String()
+	if needAuth {
+		elevated = Cmd("sudo", "-S", "-p", sudoPrompt, "-v").
+			Then(Cmd("printf", `%s\n`, sudoReady)).
+			Then(elevated)
+	}
+
+	sh, err := c.startPTY(ctx, opts, elevated.In(projectPath).String())
+	if err != nil {
+		return nil, err
+	}
+	if needAuth {
+		if err := sh.authenticateSudo(ctx, c.sudo.Password); err != nil {
+			sh.Close()
+			return nil, err
+		}
+	}
+	return sh, nil
+}
+
+// sudo -v 의 비밀번호 프롬프트와 인증 완료 표시. 출력에서 찾기 쉽도록 셸 출력과 겹치지 않는 문자열을 사용합니다
+const (
+	sudoPrompt      = "[sship-sudo-password]"
+	sudoReady       = "[sship-sudo-ready]"
+	sudoAuthTimeout = 30 * time.Second
+)
+
+// authenticateSudo 인증 완료 표시가 나올 때까지 출력을 읽으며, 프롬프트가 나오면 한 번만 비밀번호를 보냅니다.
+// sudo 는 프롬프트를 출력하기 전에 에코를 끄므로 비밀번호가 출력으로 돌아오지 않고,
+// 자격 증명이 캐시되어 있거나 NOPASSWD 면 프롬프트 없이 지나가므로 비밀번호를 보내지 않습니다.
+// 인증 전의 출력은 버리고, 이후 출력은 그대로 Stdout 으로 읽을 수 있습니다
+func (s *Shell) authenticateSudo(ctx context.Context, password string) error {
+	reader := bufio.NewReader(s.Stdout)
+	done := make(chan error, 1)
+	go func() {
+		var seen []byte
+		sent, ready := false, false
+		for {
+			b, err := reader.ReadByte()
+			if err != nil {
+				done <- fmt.Errorf("sudo 인증 실패: %s", strings.TrimSpace(strings.ReplaceAll(string(seen), sudoPrompt, "")))
+				return
+			}
+			seen = append(seen, b)
+			switch {
+			case bytes.HasSuffix(seen, []byte(sudoPrompt)):
+				if sent {
+					done <- fmt.Errorf("sudo 인증 실패: 비밀번호가 올바르지 않습니다")
+					return
+				}
+				sent = true
+				if _, err := io.WriteString(s.Stdin, password+"\n"); err != nil {
+					done <- fmt.Errorf("sudo 비밀번호 전달 실패: %v", err)
+					return
+				}
+			case ready && b == '\n':
+				// PTY 는 줄바꿈을 \r\n 으로 바꾸므로 표시 다음 줄바꿈까지 버림
+				done <- nil
+				return
+			case bytes.HasSuffix(seen, []byte(sudoReady)):
+				ready = true
+			}
+		}
+	}()
+
+	timer := time.NewTimer(sudoAuthTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err == nil {
+			s.Stdout = reader
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("sudo 인증 시간 초과")
+	}
+}
+
+// startPTY PTY 를 요청하고 command 를 실행합니다. command 가 비어 있으면 기본 셸을 시작합니다.
+func (c *Client) startPTY(ctx context.Context, opts ShellOptions, command string) (*Shell, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Term == "" {
+		opts.Term = "xterm-256color"
+	}
+	if opts.Cols <= 0 {
+		opts.Cols = 80
+	}
+	if opts.Rows <= 0 {
+		opts.Rows = 24
+	}
+
+	session, err := c.newSession()
+	if err != nil {
+		return nil, err
+	}
+
+	sh := &Shell{client: c, session: session}
+	fail := func(format string, err error) (*Shell, error) {
+		c.closeSession(session)
+		return nil, fmt.Errorf(format, err)
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(opts.Term, opts.Rows, opts.Cols, modes); err != nil {
+		return fail("PTY 요청 실패: %v", err)
+	}
+	if sh.Stdin, err = session.StdinPipe(); err != nil {
+		return fail("셸 입력 연결 실패: %v", err)
+	}
+	if sh.Stdout, err = session.StdoutPipe(); err != nil {
+		return fail("셸 출력 연결 실패: %v", err)
+	}
+
+	if command != "" {
+		err = session.Start(command)
+	} else {
+		err = session.Shell()
+	}
+	if err != nil {
+		return fail("셸 시작 실패: %v", err)
+	}
+
+	sh.stop = context.AfterFunc(ctx, func() { sh.Close() })
+	return sh, nil
+}
+
+// Resize 터미널 창 크기 변경을 서버에 알립니다
+func (s *Shell) Resize(cols, rows int) error {
+	if cols <= 0 || rows <= 0 {
+		return fmt.Errorf("잘못된 터미널 크기입니다: %dx%d", cols, rows)
+	}
+	return s.session.WindowChange(rows, cols)
+}
+
+// Wait 셸이 종료될 때까지 기다립니다
+func (s *Shell) Wait() error {
+	return s.session.Wait()
+}
+
+// Close 셸 세션을 닫습니다. 여러 번 호출해도 안전합니다.
+func (s *Shell) Close() error {
+	s.closeOnce.Do(func() {
+		if s.stop != nil {
+			s.stop()
+		}
+		s.client.closeSession(s.session)
+	})
+	return nil
+}
@@ -0,0 +1,185 @@
+package ssh_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestOpenShell(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("exec", sshtest.Response{Stdout: "welcome\n", Delay: 200 * time.Millisecond})
+
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	shell, err := client.OpenShell(context.Background(), ssh.ShellOptions{Dir: "/srv/my app", Cols: 120, Rows: 40})
+	if err != nil {
+		t.Fatalf("OpenShell() error = %v", err)
+	}
+	defer shell.Close()
+
+	if err := shell.Resize(100, 30); err != nil {
+		t.Fatalf("Resize() error = %v", err)
+	}
+
+	out, _ := io.ReadAll(shell.Stdout)
+	if string(out) != "welcome\n" {
+		t.Errorf("출력 = %q", out)
+	}
+	shell.Wait()
+
+	if !srv.Ran("cd -- '/srv/my app' && exec") {
+		t.Errorf("프로젝트 경로에서 셸이 시작되지 않음: %v", srv.Commands())
+	}
+	if got := strings.Join(srv.WindowSizes(), ","); got != "120x40,100x30" {
+		t.Errorf("터미널 크기 = %s", got)
+	}
+}
+
+func TestOpenShellCancel(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("exec", sshtest.Response{Delay: 10 * time.Second})
+
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	shell, err := client.OpenShell(ctx, ssh.ShellOptions{Dir: "/srv/app"})
+	if err != nil {
+		t.Fatalf("OpenShell() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		shell.Wait()
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("ctx 취소 후에도 셸이 종료되지 않음")
+	}
+}
+
+func TestComposeExec(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("exec web", sshtest.Response{Stdout: "/app # "})
+	srv.Handle("config --services", sshtest.Response{Stdout: "web\nworker\ndb\n"})
+	srv.Handle("ps --services", sshtest.Response{Stdout: "web\ndb\n"})
+
+	client, err := ssh.NewClient(srv.ConnectionConfig())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	services, err := client.ComposeServices(ctx, "/srv/app", "docker-compose.prod.yml")
+	if err != nil {
+		t.Fatalf("ComposeServices() error = %v", err)
+	}
+	want := []ssh.ComposeService{{Name: "web", Running: true}, {Name: "worker"}, {Name: "db", Running: true}}
+	if len(services) != len(want) {
+		t.Fatalf("ComposeServices() = %+v", services)
+	}
+	for i := range want {
+		if services[i] != want[i] {
+			t.Errorf("services[%d] = %+v, want %+v", i, services[i], want[i])
+		}
+	}
+
+	shell, err := client.ComposeExec(ctx, "/srv/app", "docker-compose.prod.yml", "web", nil, ssh.ShellOptions{})
+	if err != nil {
+		t.Fatalf("ComposeExec() error = %v", err)
+	}
+	defer shell.Close()
+	out, _ := io.ReadAll(shell.Stdout)
+	if string(out) != "/app # " {
+		t.Errorf("출력 = %q", out)
+	}
+	if !srv.Ran("cd -- /srv/app && docker compose -f docker-compose.prod.yml exec web sh") {
+		t.Errorf("exec 명령이 실행되지 않음: %v", srv.Commands())
+	}
+
+	if _, err := client.ComposeExec(ctx, "/srv/app", "docker-compose.prod.yml", "--privileged", nil, ssh.ShellOptions{}); err == nil {
+		t.Error("'-' 로 시작하는 서비스 이름이 허용됨")
+	}
+}
+
+func TestComposeExecSudoPassword(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt bool
+	}{
+		{name: "prompt", prompt: true},
+		// 자격 증명이 캐시되어 있거나 NOPASSWD 면 비밀번호를 보내지 않아야 함
+		{name: "cached", prompt: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := sshtest.NewServer(t)
+			input := make(chan string, 1)
+			srv.Handle("exec web", sshtest.Response{Interact: func(rw io.ReadWriter) int {
+				r := bufio.NewReader(rw)
+				if tt.prompt {
+					io.WriteString(rw, "[sship-sudo-password]")
+					line, _ := r.ReadString('\n')
+					if line != "s3cret\n" {
+						input <- line
+						return 1
+					}
+					io.WriteString(rw, "\r\n")
+				}
+				io.WriteString(rw, "[sship-sudo-ready]\r\n/app # ")
+				line, _ := r.ReadString('\n')
+				input <- line
+				return 0
+			}})
+
+			cfg := srv.ConnectionConfig()
+			cfg.Sudo = ssh.SudoConfig{Enabled: true, Password: "s3cret"}
+			cfg.Runtime = ssh.RuntimeDocker
+			client, err := ssh.NewClient(cfg)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			shell, err := client.ComposeExec(context.Background(), "/srv/app", "docker-compose.prod.yml", "web", nil, ssh.ShellOptions{})
+			if err != nil {
+				t.Fatalf("ComposeExec() error = %v", err)
+			}
+			defer shell.Close()
+
+			// 인증 과정의 출력은 버리고 컨테이너 셸 출력부터 전달
+			out := make([]byte, len("/app # "))
+			if _, err := io.ReadFull(shell.Stdout, out); err != nil || string(out) != "/app # " {
+				t.Fatalf("출력 = %q, %v", out, err)
+			}
+			io.WriteString(shell.Stdin, "exit\n")
+			if got := <-input; got != "exit\n" {
+				t.Errorf("컨테이너 셸 입력 = %q, want %q", got, "exit\n")
+			}
+
+			want := "cd -- /srv/app && sudo -S -p '[sship-sudo-password]' -v && printf '%s\\n' '[sship-sudo-ready]' && sudo -n -- docker compose -f docker-compose.prod.yml exec web sh"
+			if !srv.Ran(want) {
+				t.Errorf("실행된 명령 = %q", srv.Commands())
+			}
+		})
+	}
+}
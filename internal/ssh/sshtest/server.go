@@ -0,0 +1,467 @@
+// Package sshtest 배포 파이프라인을 go test 에서 실행하기 위한 인프로세스 SSH 서버.
+// 실제 셸 대신 등록된 규칙에 따라 명령 응답을 돌려주며, sftp 서브시스템은 로컬 파일시스템을 제공합니다.
+package sshtest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sship "github.com/lambda0x63/sship/internal/ssh"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	User     = "deploy"
+	Password = "sshtest"
+)
+
+// Response 명령 하나에 대한 응답
+type Response struct {
+	Stdout     string
+	Stderr     string
+	ExitStatus int
+	// Delay 응답 전 대기 시간. 대기 중 signal 요청이나 채널 종료가 오면 즉시 중단됩니다
+	Delay time.Duration
+	// Interact 설정하면 Stdout/Stderr 를 보낸 뒤 채널의 입출력으로 대화형 응답을 이어가며, 반환값을 종료 코드로 사용합니다
+	Interact func(rw io.ReadWriter) int
+}
+
+// HandlerFunc 수신한 명령으로 응답을 만듭니다
+type HandlerFunc func(command string) Response
+
+type rule struct {
+	pattern string
+	handler HandlerFunc
+}
+
+type Server struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+
+	mu       sync.Mutex
+	rules    []rule
+	commands []string
+	signals  []string
+	windows  []string
+	conns    []*ssh.ServerConn
+	hostCert ssh.Signer
+	userCA   ssh.PublicKey
+	stall    atomic.Bool
+	wg       sync.WaitGroup
+}
+
+// NewServer 127.0.0.1 의 임의 포트에서 서버를 시작합니다. 테스트 종료 시 자동으로 닫힙니다.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("호스트 키 생성 실패: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("호스트 키 생성 실패: %v", err)
+	}
+
+	s := &Server{hostKey: signer}
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("리스너 생성 실패: %v", err)
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	// sship 은 등록되지 않은 호스트에 연결하지 않으므로 운영자가 등록한 것처럼 키를 미리 등록
+	cfg := s.ConnectionConfig()
+	if err := sship.HostKeys().Pin(cfg.Host, cfg.Port, signer.PublicKey()); err != nil {
+		t.Fatalf("호스트 키 등록 실패: %v", err)
+	}
+	t.Cleanup(func() { sship.HostKeys().Remove(cfg.Host, cfg.Port) })
+
+	t.Cleanup(s.Close)
+	return s
+}
+
+// ConnectionConfig 이 서버에 접속하기 위한 sship 접속 설정
+func (s *Server) ConnectionConfig() sship.ConnectionConfig {
+	host, portStr, _ := net.SplitHostPort(s.listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return sship.ConnectionConfig{
+		Host:     host,
+		Port:     port,
+		User:     User,
+		Password: Password,
+	}
+}
+
+// HostKey 서버 호스트 키
+func (s *Server) HostKey() ssh.PublicKey {
+	return s.hostKey.PublicKey()
+}
+
+// UseHostCertificate 이후 연결부터 ca 로 서명한 호스트 인증서를 제시합니다 (일반 호스트 키 대신)
+func (s *Server) UseHostCertificate(t testing.TB, ca ssh.Signer, principals ...string) {
+	t.Helper()
+
+	cert := &ssh.Certificate{
+		Key:             s.hostKey.PublicKey(),
+		CertType:        ssh.HostCert,
+		KeyId:           "sshtest",
+		ValidPrincipals: principals,
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatalf("호스트 인증서 서명 실패: %v", err)
+	}
+	signer, err := ssh.NewCertSigner(cert, s.hostKey)
+	if err != nil {
+		t.Fatalf("호스트 인증서 생성 실패: %v", err)
+	}
+
+	s.mu.Lock()
+	s.hostCert = signer
+	s.mu.Unlock()
+}
+
+// TrustUserCA 이후 연결부터 ca 가 서명한 사용자 인증서로 공개키 인증을 허용합니다
+func (s *Server) TrustUserCA(ca ssh.PublicKey) {
+	s.mu.Lock()
+	s.userCA = ca
+	s.mu.Unlock()
+}
+
+// serverConfig 현재 설정으로 연결 하나에 사용할 서버 설정을 만듭니다
+func (s *Server) serverConfig() *ssh.ServerConfig {
+	s.mu.Lock()
+	hostKey, userCA := s.hostKey, s.userCA
+	if s.hostCert != nil {
+		hostKey = s.hostCert
+	}
+	s.mu.Unlock()
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == User && string(password) == Password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("인증 실패: %s", conn.User())
+		},
+	}
+	if userCA != nil {
+		checker := &ssh.CertChecker{
+			IsUserAuthority: func(auth ssh.PublicKey) bool {
+				return bytes.Equal(auth.Marshal(), userCA.Marshal())
+			},
+		}
+		config.PublicKeyCallback = checker.Authenticate
+	}
+	config.AddHostKey(hostKey)
+	return config
+}
+
+// Handle 명령에 pattern 이 포함되면 resp 를 돌려줍니다. 먼저 등록한 규칙이 우선합니다.
+func (s *Server) Handle(pattern string, resp Response) {
+	s.HandleFunc(pattern, func(string) Response { return resp })
+}
+
+func (s *Server) HandleFunc(pattern string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = append(s.rules, rule{pattern: pattern, handler: handler})
+}
+
+// Commands 지금까지 수신한 exec 명령 목록
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// Signals 지금까지 수신한 signal 요청 목록
+func (s *Server) Signals() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.signals...)
+}
+
+// WindowSizes pty-req 와 window-change 로 받은 터미널 크기 목록 ("80x24" 형식)
+func (s *Server) WindowSizes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.windows...)
+}
+
+func (s *Server) recordWindow(payload []byte, term bool) bool {
+	var size struct {
+		Columns, Rows uint32
+	}
+	if term {
+		var pty struct {
+			Term          string
+			Columns, Rows uint32
+			Width, Height uint32
+			Modes         string
+		}
+		if err := ssh.Unmarshal(payload, &pty); err != nil {
+			return false
+		}
+		size.Columns, size.Rows = pty.Columns, pty.Rows
+	} else {
+		var wc struct {
+			Columns, Rows uint32
+			Width, Height uint32
+		}
+		if err := ssh.Unmarshal(payload, &wc); err != nil {
+			return false
+		}
+		size.Columns, size.Rows = wc.Columns, wc.Rows
+	}
+
+	s.mu.Lock()
+	s.windows = append(s.windows, fmt.Sprintf("%dx%d", size.Columns, size.Rows))
+	s.mu.Unlock()
+	return true
+}
+
+// StallKeepAlive true 이면 keepalive 등 전역 요청에 응답하지 않습니다.
+// NAT 가 유휴 연결을 조용히 끊은 상황을 흉내냅니다.
+func (s *Server) StallKeepAlive(stall bool) {
+	s.stall.Store(stall)
+}
+
+// DropConnections 현재 열린 모든 SSH 연결을 서버 쪽에서 끊습니다
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// Ran pattern 을 포함한 명령을 수신했는지 여부
+func (s *Server) Ran(pattern string) bool {
+	for _, cmd := range s.Commands() {
+		if strings.Contains(cmd, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, s.serverConfig())
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer sconn.Close()
+
+	s.mu.Lock()
+	s.conns = append(s.conns, sconn)
+	s.mu.Unlock()
+
+	go func() {
+		for req := range reqs {
+			// 응답하지 않으면 클라이언트 keepalive 가 시간 초과됨
+			if req.WantReply && !s.stall.Load() {
+				req.Reply(false, nil)
+			}
+		}
+	}()
+
+	for newChan := range chans {
+		switch newChan.ChannelType() {
+		case "session":
+			ch, chReqs, err := newChan.Accept()
+			if err != nil {
+				continue
+			}
+			go s.handleSession(ch, chReqs)
+		case "direct-tcpip":
+			go s.handleDirectTCPIP(newChan)
+		default:
+			newChan.Reject(ssh.UnknownChannelType, "지원하지 않는 채널")
+		}
+	}
+}
+
+// handleDirectTCPIP 포트 포워딩 요청을 로컬 주소로 연결합니다 (RFC 4254 7.2)
+func (s *Server) handleDirectTCPIP(newChan ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChan.ExtraData(), &payload); err != nil {
+		newChan.Reject(ssh.ConnectionFailed, "잘못된 요청")
+		return
+	}
+
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		newChan.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+
+	ch, reqs, err := newChan.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(ch, target)
+		ch.CloseWrite()
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(target, ch)
+		if tcp, ok := target.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+	ch.Close()
+	target.Close()
+}
+
+func (s *Server) handleSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+
+	interrupted := make(chan struct{})
+	var once sync.Once
+	interrupt := func() { once.Do(func() { close(interrupted) }) }
+
+	for req := range reqs {
+		switch req.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+
+			// 실행 중에도 signal 요청을 받을 수 있도록 나머지 요청은 별도로 처리
+			go func() {
+				for req := range reqs {
+					if req.Type == "window-change" {
+						s.recordWindow(req.Payload, false)
+						continue
+					}
+					if req.Type == "signal" {
+						var sig struct{ Signal string }
+						ssh.Unmarshal(req.Payload, &sig)
+						s.mu.Lock()
+						s.signals = append(s.signals, sig.Signal)
+						s.mu.Unlock()
+						interrupt()
+					}
+					if req.WantReply {
+						req.Reply(false, nil)
+					}
+				}
+				// 클라이언트가 채널을 닫은 경우
+				interrupt()
+			}()
+
+			s.exec(ch, payload.Command, interrupted)
+			return
+		case "pty-req":
+			req.Reply(s.recordWindow(req.Payload, true), nil)
+		case "subsystem":
+			var payload struct{ Name string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil || payload.Name != "sftp" {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+			go ssh.DiscardRequests(reqs)
+
+			// 로컬 파일시스템을 그대로 제공하므로 테스트에서는 t.TempDir() 경로를 사용
+			server, err := sftp.NewServer(ch)
+			if err != nil {
+				return
+			}
+			server.Serve()
+			server.Close()
+			return
+		default:
+			if req.WantReply {
+				req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func (s *Server) exec(ch ssh.Channel, command string, interrupted <-chan struct{}) {
+	s.mu.Lock()
+	s.commands = append(s.commands, command)
+	resp := Response{}
+	for _, r := range s.rules {
+		if strings.Contains(command, r.pattern) {
+			handler := r.handler
+			s.mu.Unlock()
+			resp = handler(command)
+			s.mu.Lock()
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-interrupted:
+			sendExitStatus(ch, 143)
+			return
+		}
+	}
+
+	ch.Write([]byte(resp.Stdout))
+	ch.Stderr().Write([]byte(resp.Stderr))
+	if resp.Interact != nil {
+		resp.ExitStatus = resp.Interact(ch)
+	}
+	sendExitStatus(ch, resp.ExitStatus)
+}
+
+func sendExitStatus(ch ssh.Channel, status int) {
+	ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+}
@@ -0,0 +1,61 @@
+package ssh
+
+import (
+	"io"
+	"strings"
+)
+
+// SudoConfig 권한이 필요한 명령을 sudo 로 실행하기 위한 설정.
+// root 가 아닌 계정으로 접속해 docker/git 만 권한 상승하는 경우에 사용합니다.
+type SudoConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Commands sudo 로 실행할 프로그램. 비어있으면 git 과 컨테이너 런타임 명령 (docker, docker-compose, podman, podman-compose)
+	Commands []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// Password sudo 비밀번호. 비어있으면 비밀번호 없이(-n) 실행하며 NOPASSWD 설정이 필요합니다
+	Password string `json:"-" yaml:"password,omitempty"`
+}
+
+var defaultSudoCommands = []string{"docker", "docker-compose", "podman", "podman-compose", "git"}
+
+func (s SudoConfig) elevates(program string) bool {
+	commands := s.Commands
+	if len(commands) == 0 {
+		commands = defaultSudoCommands
+	}
+	for _, name := range commands {
+		if name == program {
+			return true
+		}
+	}
+	return false
+}
+
+// apply 대상 프로그램을 실행하는 단계에 sudo 를 붙입니다.
+// 비밀번호가 있으면 sudo 마다 한 줄씩 읽도록 표준 입력으로 보낼 내용을 함께 반환합니다.
+func (s SudoConfig) apply(cmd Command) (Command, io.Reader) {
+	if !s.Enabled {
+		return cmd, nil
+	}
+
+	prefix := []string{"sudo", "-n", "--"}
+	if s.Password != "" {
+		// 프롬프트가 출력에 섞이지 않도록 빈 프롬프트 사용
+		prefix = []string{"sudo", "-S", "-p", "", "--"}
+	}
+
+	steps := make([]commandStep, len(cmd.steps))
+	elevated := 0
+	for i, step := range cmd.steps {
+		if program := step.program(); program != "" && s.elevates(program) {
+			step.args = append(append([]string(nil), prefix...), step.args...)
+			elevated++
+		}
+		steps[i] = step
+	}
+	cmd.steps = steps
+
+	if elevated == 0 || s.Password == "" {
+		return cmd, nil
+	}
+	return cmd, strings.NewReader(strings.Repeat(s.Password+"\n", elevated))
+}
@@ -0,0 +1,81 @@
+package ssh
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSudoApply(t *testing.T) {
+	cmd := Cmd("git", "pull", "origin", "main").
+		Then(Cmd("docker", "compose", "up", "-d")).
+		Then(Cmd("echo", "done")).
+		In("/srv/app")
+
+	tests := []struct {
+		name      string
+		sudo      SudoConfig
+		want      string
+		wantStdin string
+	}{
+		{
+			name: "disabled",
+			sudo: SudoConfig{},
+			want: "cd -- /srv/app && git pull origin main && docker compose up -d && echo done",
+		},
+		{
+			name: "passwordless",
+			sudo: SudoConfig{Enabled: true},
+			want: "cd -- /srv/app && sudo -n -- git pull origin main && sudo -n -- docker compose up -d && echo done",
+		},
+		{
+			name:      "password per elevated step",
+			sudo:      SudoConfig{Enabled: true, Commands: []string{"docker"}, Password: "s3cret"},
+			want:      "cd -- /srv/app && git pull origin main && sudo -S -p '' -- docker compose up -d && echo done",
+			wantStdin: "s3cret\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stdin := tt.sudo.apply(cmd)
+			if got.String() != tt.want {
+				t.Errorf("apply() = %q, want %q", got.String(), tt.want)
+			}
+			var input string
+			if stdin != nil {
+				b, _ := io.ReadAll(stdin)
+				input = string(b)
+			}
+			if input != tt.wantStdin {
+				t.Errorf("stdin = %q, want %q", input, tt.wantStdin)
+			}
+		})
+	}
+
+	// 원본 명령은 바뀌지 않아야 함
+	if cmd.String() != tests[0].want {
+		t.Errorf("원본 명령이 변경됨: %q", cmd.String())
+	}
+}
+
+func TestSudoApplyRaw(t *testing.T) {
+	sudo := SudoConfig{Enabled: true}
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"docker ps", "sudo -n -- docker ps"},
+		{"cd /srv/app && docker compose up -d 2>&1 | tail -n 5", "cd /srv/app && sudo -n -- docker compose up -d 2>&1 | tail -n 5"},
+		{"echo 'a && docker' ; git pull", "echo 'a && docker' ; sudo -n -- git pull"},
+		{"ls\ndocker ps\n", "ls ; sudo -n -- docker ps"},
+		// 나눌 수 없는 구문은 첫 프로그램에만 적용
+		{"docker ps -q $(echo x)", "sudo -n -- docker ps -q $(echo x)"},
+		{"echo $(docker ps)", "echo $(docker ps)"},
+	}
+	for _, tt := range tests {
+		got, _ := sudo.apply(Raw(tt.raw))
+		if got.String() != tt.want {
+			t.Errorf("apply(Raw(%q)) = %q, want %q", tt.raw, got.String(), tt.want)
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package validator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/lambda0x63/sship/internal/config"
 	"github.com/lambda0x63/sship/internal/ssh"
 )
 
+// 셸이 명령을 찾지 못했을 때의 종료 코드
+const exitCommandNotFound = 127
+
 type ValidationResult struct {
 	Passed   bool
 	Checks   []CheckResult
@@ -22,22 +28,24 @@ type CheckResult struct {
 }
 
 type PreDeployValidator struct {
-	config *config.Config
+	config    *config.Config
+	connector ssh.Connector
 }
 
-func NewPreDeployValidator(cfg *config.Config) *PreDeployValidator {
+func NewPreDeployValidator(cfg *config.Config, connector ssh.Connector) *PreDeployValidator {
 	return &PreDeployValidator{
-		config: cfg,
+		config:    cfg,
+		connector: connector,
 	}
 }
 
-func (v *PreDeployValidator) Validate(projectName string) (*ValidationResult, error) {
+func (v *PreDeployValidator) Validate(ctx context.Context, projectName string) (*ValidationResult, error) {
 	proj, exists := v.config.GetProject(projectName)
 	if !exists {
 		return nil, fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", projectName)
 	}
 
-	client, err := ssh.NewClient(proj.Server)
+	client, err := v.connector.Connect(proj.Server)
 	if err != nil {
 		return nil, fmt.Errorf("SSH 연결 실패: %v", err)
 	}
@@ -50,7 +58,7 @@ func (v *PreDeployValidator) Validate(projectName string) (*ValidationResult, er
 		Errors:   []string{},
 	}
 
-	check := v.checkSSHConnection(client)
+	check := v.checkSSHConnection(ctx, client)
 	result.Checks = append(result.Checks, check)
 	if !check.Passed {
 		result.Passed = false
@@ -58,35 +66,35 @@ func (v *PreDeployValidator) Validate(projectName string) (*ValidationResult, er
 		return result, nil
 	}
 
-	check = v.checkGitRepository(client, proj.Path)
+	check = v.checkGitRepository(ctx, client, proj.Path)
 	result.Checks = append(result.Checks, check)
 	if !check.Passed {
 		result.Passed = false
 		result.Errors = append(result.Errors, check.Message)
 	}
 
-	check = v.checkRequiredFiles(client, proj)
+	check = v.checkRequiredFiles(ctx, client, proj)
 	result.Checks = append(result.Checks, check)
 	if !check.Passed {
 		result.Passed = false
 		result.Errors = append(result.Errors, check.Message)
 	}
 
-	check = v.checkDockerStatus(client)
+	check = v.checkDockerStatus(ctx, client)
 	result.Checks = append(result.Checks, check)
 	if !check.Passed {
 		result.Passed = false
 		result.Errors = append(result.Errors, check.Message)
 	}
 
-	check = v.checkDiskSpace(client, proj.Path)
+	check = v.checkDiskSpace(ctx, client, proj.Path)
 	result.Checks = append(result.Checks, check)
 	if !check.Passed {
 		result.Warnings = append(result.Warnings, check.Message)
 	}
 
 	if proj.Port > 0 {
-		check = v.checkPortAvailability(client, proj.Port)
+		check = v.checkPortAvailability(ctx, client, proj.Port)
 		result.Checks = append(result.Checks, check)
 		if !check.Passed {
 			result.Warnings = append(result.Warnings, check.Message)
@@ -96,8 +104,8 @@ func (v *PreDeployValidator) Validate(projectName string) (*ValidationResult, er
 	return result, nil
 }
 
-func (v *PreDeployValidator) checkSSHConnection(client *ssh.Client) CheckResult {
-	err := client.CheckConnection()
+func (v *PreDeployValidator) checkSSHConnection(ctx context.Context, client ssh.RemoteExecutor) CheckResult {
+	err := client.CheckConnection(ctx)
 	if err != nil {
 		return CheckResult{
 			Name:    "SSH 연결",
@@ -112,18 +120,30 @@ func (v *PreDeployValidator) checkSSHConnection(client *ssh.Client) CheckResult
 	}
 }
 
-func (v *PreDeployValidator) checkGitRepository(client *ssh.Client, projectPath string) CheckResult {
-	command := fmt.Sprintf("cd %s && git status --porcelain 2>&1", projectPath)
-	output, err := client.ExecuteCommand(command)
-
+func (v *PreDeployValidator) checkGitRepository(ctx context.Context, client ssh.RemoteExecutor, projectPath string) CheckResult {
+	// 저장소 밖에서 git rev-parse 는 종료 코드 128 로 끝남
+	_, err := client.Execute(ctx, ssh.Cmd("git", "rev-parse", "--is-inside-work-tree").In(projectPath))
+	if status, ok := ssh.ExitStatus(err); ok {
+		message := "프로젝트 경로에 접근할 수 없습니다"
+		if status == 128 {
+			message = "Git 저장소가 아닙니다"
+		}
+		return CheckResult{
+			Name:    "Git 저장소",
+			Passed:  false,
+			Message: message,
+		}
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "not a git repository") {
-			return CheckResult{
-				Name:    "Git 저장소",
-				Passed:  false,
-				Message: "Git 저장소가 아닙니다",
-			}
+		return CheckResult{
+			Name:    "Git 저장소",
+			Passed:  false,
+			Message: fmt.Sprintf("Git 상태 확인 실패: %v", err),
 		}
+	}
+
+	result, err := client.Execute(ctx, ssh.Cmd("git", "status", "--porcelain").In(projectPath))
+	if err != nil {
 		return CheckResult{
 			Name:    "Git 저장소",
 			Passed:  false,
@@ -131,7 +151,7 @@ func (v *PreDeployValidator) checkGitRepository(client *ssh.Client, projectPath
 		}
 	}
 
-	if strings.TrimSpace(output) != "" {
+	if strings.TrimSpace(result.Stdout) != "" {
 		return CheckResult{
 			Name:    "Git 저장소",
 			Passed:  true,
@@ -146,21 +166,27 @@ func (v *PreDeployValidator) checkGitRepository(client *ssh.Client, projectPath
 	}
 }
 
-func (v *PreDeployValidator) checkRequiredFiles(client *ssh.Client, proj config.Project) CheckResult {
+func (v *PreDeployValidator) checkRequiredFiles(ctx context.Context, client ssh.RemoteExecutor, proj config.Project) CheckResult {
 	requiredFiles := []string{
 		proj.DockerCompose,
 	}
 
-	if proj.EnvFile != "" {
+	// env 를 지정했으면 배포할 때 sship 이 env_file 을 만듭니다
+	if proj.EnvFile != "" && len(proj.Env) == 0 {
 		requiredFiles = append(requiredFiles, proj.EnvFile)
 	}
 
 	var missingFiles []string
 	for _, file := range requiredFiles {
-		command := fmt.Sprintf("cd %s && test -f %s", proj.Path, file)
-		_, err := client.ExecuteCommand(command)
-		if err != nil {
+		_, err := client.Execute(ctx, ssh.Cmd("test", "-f", file).In(proj.Path))
+		if _, ok := ssh.ExitStatus(err); ok {
 			missingFiles = append(missingFiles, file)
+		} else if err != nil {
+			return CheckResult{
+				Name:    "필수 파일",
+				Passed:  false,
+				Message: fmt.Sprintf("필수 파일 확인 실패: %v", err),
+			}
 		}
 	}
 
@@ -179,35 +205,65 @@ func (v *PreDeployValidator) checkRequiredFiles(client *ssh.Client, proj config.
 	}
 }
 
-func (v *PreDeployValidator) checkDockerStatus(client *ssh.Client) CheckResult {
-	_, err := client.ExecuteCommand("docker info > /dev/null 2>&1")
+func (v *PreDeployValidator) checkDockerStatus(ctx context.Context, client ssh.RemoteExecutor) CheckResult {
+	// compose 명령 형태(docker compose, docker-compose, podman-compose ...)를 감지해 연결에 기록
+	rt, err := client.ContainerRuntime(ctx)
+	if errors.Is(err, ssh.ErrNoRuntime) {
+		return CheckResult{
+			Name:    "Docker",
+			Passed:  false,
+			Message: "Docker Compose가 설치되지 않았습니다 (docker compose, docker-compose, podman-compose 모두 없음)",
+		}
+	}
 	if err != nil {
 		return CheckResult{
 			Name:    "Docker",
 			Passed:  false,
-			Message: "Docker 데몬이 실행 중이지 않습니다",
+			Message: fmt.Sprintf("컨테이너 런타임 확인 실패: %v", err),
 		}
 	}
 
-	_, err = client.ExecuteCommand("docker compose version > /dev/null 2>&1")
+	_, err = client.Execute(ctx, ssh.Cmd(rt.Engine, "info").Quiet())
+	if status, ok := ssh.ExitStatus(err); ok {
+		message := "Docker 데몬이 실행 중이지 않습니다"
+		switch {
+		case rt.Engine == "podman" && status == exitCommandNotFound:
+			message = "Podman이 설치되지 않았습니다"
+		case rt.Engine == "podman":
+			message = "Podman 상태 확인 실패"
+		case status == exitCommandNotFound:
+			message = "Docker가 설치되지 않았습니다"
+		}
+		return CheckResult{
+			Name:    "Docker",
+			Passed:  false,
+			Message: message,
+		}
+	}
 	if err != nil {
 		return CheckResult{
 			Name:    "Docker",
 			Passed:  false,
-			Message: "Docker Compose가 설치되지 않았습니다",
+			Message: fmt.Sprintf("Docker 상태 확인 실패: %v", err),
 		}
 	}
 
 	return CheckResult{
 		Name:    "Docker",
 		Passed:  true,
-		Message: "Docker 및 Docker Compose 정상",
+		Message: fmt.Sprintf("컨테이너 런타임 정상 (%s)", rt),
 	}
 }
 
-func (v *PreDeployValidator) checkDiskSpace(client *ssh.Client, projectPath string) CheckResult {
-	command := fmt.Sprintf("df -h %s | tail -1 | awk '{print $5}' | sed 's/%%//'", projectPath)
-	output, err := client.ExecuteCommand(command)
+func (v *PreDeployValidator) checkDiskSpace(ctx context.Context, client ssh.RemoteExecutor, projectPath string) CheckResult {
+	result, err := client.Execute(ctx, ssh.Cmd("df", "-h", "--", projectPath).
+		Pipe(ssh.Cmd("tail", "-1")).
+		Pipe(ssh.Cmd("awk", "{print $5}")).
+		Pipe(ssh.Cmd("sed", "s/%//")))
+	var usage int
+	if err == nil {
+		usage, err = strconv.Atoi(strings.TrimSpace(result.Stdout))
+	}
 	if err != nil {
 		return CheckResult{
 			Name:    "디스크 공간",
@@ -216,37 +272,43 @@ func (v *PreDeployValidator) checkDiskSpace(client *ssh.Client, projectPath stri
 		}
 	}
 
-	usage := strings.TrimSpace(output)
-	if usage >= "90" {
+	if usage >= 90 {
 		return CheckResult{
 			Name:    "디스크 공간",
 			Passed:  false,
-			Message: fmt.Sprintf("디스크 사용률이 높습니다: %s%%", usage),
+			Message: fmt.Sprintf("디스크 사용률이 높습니다: %d%%", usage),
 		}
 	}
 
 	return CheckResult{
 		Name:    "디스크 공간",
 		Passed:  true,
-		Message: fmt.Sprintf("디스크 사용률: %s%%", usage),
+		Message: fmt.Sprintf("디스크 사용률: %d%%", usage),
 	}
 }
 
-func (v *PreDeployValidator) checkPortAvailability(client *ssh.Client, port int) CheckResult {
-	command := fmt.Sprintf("lsof -i:%d > /dev/null 2>&1", port)
-	_, err := client.ExecuteCommand(command)
+func (v *PreDeployValidator) checkPortAvailability(ctx context.Context, client ssh.RemoteExecutor, port int) CheckResult {
+	// lsof 는 해당 포트를 쓰는 프로세스가 없으면 종료 코드 1
+	_, err := client.Execute(ctx, ssh.Cmd("lsof", fmt.Sprintf("-i:%d", port)).Quiet())
 
-	if err != nil {
+	switch status, ok := ssh.ExitStatus(err); {
+	case err == nil:
+		return CheckResult{
+			Name:    "포트 확인",
+			Passed:  false,
+			Message: fmt.Sprintf("포트 %d가 이미 사용 중입니다", port),
+		}
+	case ok && status == 1:
 		return CheckResult{
 			Name:    "포트 확인",
 			Passed:  true,
 			Message: fmt.Sprintf("포트 %d 사용 가능", port),
 		}
-	}
-
-	return CheckResult{
-		Name:    "포트 확인",
-		Passed:  false,
-		Message: fmt.Sprintf("포트 %d가 이미 사용 중입니다", port),
+	default:
+		return CheckResult{
+			Name:    "포트 확인",
+			Passed:  true,
+			Message: fmt.Sprintf("포트 %d 확인 실패 (경고): %v", port, err),
+		}
 	}
 }
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lambda0x63/sship/internal/config"
+	"github.com/lambda0x63/sship/internal/ssh"
+	"github.com/lambda0x63/sship/internal/ssh/sshtest"
+)
+
+func TestValidate(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("df -h", sshtest.Response{Stdout: "42\n"})
+	srv.Handle("compose version", sshtest.Response{ExitStatus: 127})
+
+	cfg := &config.Config{Projects: map[string]config.Project{
+		"app": {
+			Server:        srv.ConnectionConfig(),
+			Path:          "/srv/app",
+			DockerCompose: "docker-compose.prod.yml",
+		},
+	}}
+
+	result, err := NewPreDeployValidator(cfg, ssh.DirectConnector).Validate(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Passed {
+		t.Fatalf("compose 명령이 없는데 통과함: %+v", result.Checks)
+	}
+
+	checks := make(map[string]CheckResult)
+	for _, check := range result.Checks {
+		checks[check.Name] = check
+	}
+	if !checks["Git 저장소"].Passed || !checks["필수 파일"].Passed {
+		t.Errorf("git/파일 확인이 실패함: %+v", result.Checks)
+	}
+	if checks["Docker"].Passed {
+		t.Errorf("Docker 확인이 통과함: %+v", checks["Docker"])
+	}
+	if got := checks["디스크 공간"].Message; got != "디스크 사용률: 42%" {
+		t.Errorf("디스크 메시지 = %q", got)
+	}
+}
+
+func TestValidateExitStatuses(t *testing.T) {
+	srv := sshtest.NewServer(t)
+	srv.Handle("rev-parse", sshtest.Response{Stderr: "fatal: not a git repository\n", ExitStatus: 128})
+	srv.Handle("test -f", sshtest.Response{ExitStatus: 1})
+	srv.Handle("docker info", sshtest.Response{ExitStatus: 127})
+	srv.Handle("df -h", sshtest.Response{Stdout: "100\n"})
+	srv.Handle("lsof", sshtest.Response{ExitStatus: 1})
+
+	cfg := &config.Config{Projects: map[string]config.Project{
+		"app": {
+			Server:        srv.ConnectionConfig(),
+			Path:          "/srv/app",
+			DockerCompose: "docker-compose.prod.yml",
+			Port:          8080,
+		},
+	}}
+
+	result, err := NewPreDeployValidator(cfg, ssh.DirectConnector).Validate(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Git 저장소": "Git 저장소가 아닙니다",
+		"필수 파일":   "필수 파일 누락: docker-compose.prod.yml",
+		"Docker":  "Docker가 설치되지 않았습니다",
+		"디스크 공간":  "디스크 사용률이 높습니다: 100%",
+		"포트 확인":   "포트 8080 사용 가능",
+	}
+	for _, check := range result.Checks {
+		if msg, ok := want[check.Name]; ok && check.Message != msg {
+			t.Errorf("%s 메시지 = %q, want %q", check.Name, check.Message, msg)
+		}
+	}
+}